@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_items_deleted_at;
+DROP INDEX IF EXISTS idx_merchants_deleted_at;
+
+ALTER TABLE items DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE merchants DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,5 @@
+ALTER TABLE merchants ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE items ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_merchants_deleted_at ON merchants (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_items_deleted_at ON items (deleted_at);
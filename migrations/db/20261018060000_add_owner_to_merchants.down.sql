@@ -0,0 +1,44 @@
+DROP INDEX IF EXISTS idx_merchants_owner_id;
+
+ALTER TABLE merchants DROP COLUMN IF EXISTS owner_id;
+
+-- superadmin yang emailnya juga terdaftar sebagai admin digabung ke akun admin tersebut,
+-- jika tidak update di bawah melanggar idx_users_email_role_unique (email, role).
+-- Data milik superadmin dipindahkan ke akun admin dulu, baru baris superadmin dihapus.
+DO $$
+DECLARE
+    ref RECORD;
+BEGIN
+    CREATE TEMP TABLE superadmin_merge ON COMMIT DROP AS
+    SELECT s.id AS superadmin_id, a.id AS admin_id
+    FROM users s
+    JOIN users a ON a.email = s.email AND a.role = 'admin'
+    WHERE s.role = 'superadmin';
+
+    -- tabel dari migrasi berikutnya hanya ada jika migrasi tersebut belum di-rollback
+    FOR ref IN
+        SELECT c.table_name, c.column_name
+        FROM information_schema.columns c
+        JOIN (VALUES
+            ('orders', 'user_id'),
+            ('delivery_estimate', 'user_id'),
+            ('reviews', 'user_id'),
+            ('promo_codes', 'created_by'),
+            ('promo_code_redemptions', 'user_id')
+        ) AS t (table_name, column_name)
+            ON t.table_name = c.table_name AND t.column_name = c.column_name
+        WHERE c.table_schema = current_schema()
+    LOOP
+        EXECUTE format(
+            'UPDATE %I SET %I = m.admin_id FROM superadmin_merge m WHERE %I.%I = m.superadmin_id',
+            ref.table_name, ref.column_name, ref.table_name, ref.column_name
+        );
+    END LOOP;
+
+    DELETE FROM users u
+    USING superadmin_merge m
+    WHERE u.id = m.superadmin_id;
+END $$;
+
+-- postgres tidak bisa menghapus nilai enum, superadmin dikembalikan menjadi admin
+UPDATE users SET role = 'admin' WHERE role = 'superadmin';
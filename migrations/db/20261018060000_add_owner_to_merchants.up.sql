@@ -0,0 +1,6 @@
+ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'superadmin';
+
+-- merchant lama tidak memiliki owner sehingga hanya bisa dikelola superadmin
+ALTER TABLE merchants ADD COLUMN owner_id UUID REFERENCES users (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_merchants_owner_id ON merchants (owner_id);
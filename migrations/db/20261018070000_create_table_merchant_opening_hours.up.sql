@@ -0,0 +1,23 @@
+ALTER TABLE merchants ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Jakarta';
+
+CREATE TABLE merchant_opening_hours (
+    id UUID PRIMARY KEY,
+    merchant_id UUID NOT NULL REFERENCES merchants (id) ON DELETE CASCADE,
+    day_of_week SMALLINT NOT NULL CHECK (day_of_week BETWEEN 0 AND 6), -- 0 = Minggu
+    open_minute SMALLINT NOT NULL CHECK (open_minute BETWEEN 0 AND 1439),
+    close_minute SMALLINT NOT NULL CHECK (close_minute BETWEEN 0 AND 1439)
+);
+
+CREATE INDEX idx_merchant_opening_hours_merchant_id ON merchant_opening_hours (merchant_id);
+
+CREATE TABLE merchant_closures (
+    id UUID PRIMARY KEY,
+    merchant_id UUID NOT NULL REFERENCES merchants (id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    reason VARCHAR(100),
+
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_merchant_closures_merchant_id ON merchant_closures (merchant_id);
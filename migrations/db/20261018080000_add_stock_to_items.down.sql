@@ -0,0 +1,3 @@
+ALTER TABLE items
+    DROP COLUMN IF EXISTS is_available,
+    DROP COLUMN IF EXISTS stock;
@@ -0,0 +1,3 @@
+ALTER TABLE items
+    ADD COLUMN stock INTEGER CHECK (stock >= 0), -- NULL berarti stok tidak dihitung
+    ADD COLUMN is_available BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,4 @@
+ALTER TABLE order_items DROP COLUMN IF EXISTS options;
+
+DROP TABLE IF EXISTS item_modifier_options;
+DROP TABLE IF EXISTS item_modifier_groups;
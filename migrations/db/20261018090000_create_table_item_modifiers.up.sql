@@ -0,0 +1,26 @@
+CREATE TABLE item_modifier_groups (
+    id UUID PRIMARY KEY,
+    item_id UUID NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+    name VARCHAR(30) NOT NULL,
+    is_required BOOLEAN NOT NULL DEFAULT FALSE,
+    min_select INTEGER NOT NULL DEFAULT 0 CHECK (min_select >= 0),
+    max_select INTEGER NOT NULL CHECK (max_select >= 1),
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM clock_timestamp()) * 1e9)::BIGINT,
+
+    CHECK (max_select >= min_select)
+);
+
+CREATE INDEX idx_item_modifier_groups_item_id ON item_modifier_groups (item_id);
+
+CREATE TABLE item_modifier_options (
+    id UUID PRIMARY KEY,
+    group_id UUID NOT NULL REFERENCES item_modifier_groups (id) ON DELETE CASCADE,
+    name VARCHAR(30) NOT NULL,
+    price_delta NUMERIC(15,2) NOT NULL DEFAULT 0, -- tambahan harga, boleh negatif
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM clock_timestamp()) * 1e9)::BIGINT
+);
+
+CREATE INDEX idx_item_modifier_options_group_id ON item_modifier_options (group_id);
+
+-- pilihan modifier disimpan apa adanya supaya riwayat order tetap utuh
+ALTER TABLE order_items ADD COLUMN options JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,3 @@
+ALTER TABLE merchants
+    DROP COLUMN IF EXISTS min_order_value,
+    DROP COLUMN IF EXISTS max_delivery_km;
@@ -0,0 +1,4 @@
+-- radius antar dalam km, nilai awal mengikuti aturan lama 3 km
+ALTER TABLE merchants
+    ADD COLUMN max_delivery_km NUMERIC(6,2) NOT NULL DEFAULT 3 CHECK (max_delivery_km > 0),
+    ADD COLUMN min_order_value NUMERIC(15,2) NOT NULL DEFAULT 0 CHECK (min_order_value >= 0);
@@ -0,0 +1,15 @@
+CREATE TABLE reviews (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    merchant_id UUID NOT NULL REFERENCES merchants (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    comment TEXT,
+    is_hidden BOOLEAN NOT NULL DEFAULT FALSE, -- disembunyikan lewat moderasi admin
+    hidden_reason VARCHAR(200),
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM clock_timestamp()) * 1e9)::BIGINT,
+
+    CONSTRAINT uq_reviews_order_merchant UNIQUE (order_id, merchant_id)
+);
+
+CREATE INDEX idx_reviews_merchant_id ON reviews (merchant_id) WHERE NOT is_hidden;
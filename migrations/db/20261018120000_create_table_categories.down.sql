@@ -0,0 +1,23 @@
+-- NOT VALID: merchant dan item yang memakai kategori buatan admin tetap dipertahankan,
+-- constraint hanya berlaku untuk data baru
+ALTER TABLE merchants ADD CONSTRAINT merchants_merchant_category_check CHECK (
+    merchant_category IN (
+        'SmallRestaurant',
+        'MediumRestaurant',
+        'LargeRestaurant',
+        'MerchandiseRestaurant',
+        'BoothKiosk',
+        'ConvenienceStore'
+    )
+) NOT VALID;
+ALTER TABLE items ADD CONSTRAINT items_product_category_check CHECK (
+    product_category IN (
+        'Beverage',
+        'Food',
+        'Snack',
+        'Condiments',
+        'Additions'
+    )
+) NOT VALID;
+
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,25 @@
+CREATE TABLE categories (
+    id UUID PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('merchant', 'product')),
+    name VARCHAR(30) NOT NULL CHECK (char_length(name) >= 2),
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM clock_timestamp()) * 1e9)::BIGINT,
+
+    CONSTRAINT uq_categories_kind_name UNIQUE (kind, name)
+);
+
+INSERT INTO categories (id, kind, name) VALUES
+    (gen_random_uuid(), 'merchant', 'SmallRestaurant'),
+    (gen_random_uuid(), 'merchant', 'MediumRestaurant'),
+    (gen_random_uuid(), 'merchant', 'LargeRestaurant'),
+    (gen_random_uuid(), 'merchant', 'MerchandiseRestaurant'),
+    (gen_random_uuid(), 'merchant', 'BoothKiosk'),
+    (gen_random_uuid(), 'merchant', 'ConvenienceStore'),
+    (gen_random_uuid(), 'product', 'Beverage'),
+    (gen_random_uuid(), 'product', 'Food'),
+    (gen_random_uuid(), 'product', 'Snack'),
+    (gen_random_uuid(), 'product', 'Condiments'),
+    (gen_random_uuid(), 'product', 'Additions');
+
+-- daftar kategori sekarang diatur lewat tabel categories
+ALTER TABLE merchants DROP CONSTRAINT IF EXISTS merchants_merchant_category_check;
+ALTER TABLE items DROP CONSTRAINT IF EXISTS items_product_category_check;
@@ -0,0 +1,8 @@
+CREATE TABLE item_tags (
+    item_id UUID NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+    tag VARCHAR(30) NOT NULL, -- huruf kecil, contoh: halal, vegetarian, contains-nuts
+
+    PRIMARY KEY (item_id, tag)
+);
+
+CREATE INDEX idx_item_tags_tag ON item_tags (tag);
@@ -0,0 +1,12 @@
+DELETE FROM order_items oi WHERE NOT EXISTS (SELECT 1 FROM items i WHERE i.id = oi.item_id);
+DELETE FROM order_items oi WHERE NOT EXISTS (SELECT 1 FROM merchants m WHERE m.id = oi.merchant_id);
+
+ALTER TABLE order_items
+    ADD CONSTRAINT fk_item_id FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_merchant_id FOREIGN KEY (merchant_id) REFERENCES merchants (id) ON DELETE CASCADE;
+
+ALTER TABLE order_items
+    DROP COLUMN IF EXISTS merchant_name,
+    DROP COLUMN IF EXISTS unit_price,
+    DROP COLUMN IF EXISTS product_category,
+    DROP COLUMN IF EXISTS item_name;
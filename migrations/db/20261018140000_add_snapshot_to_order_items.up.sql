@@ -0,0 +1,21 @@
+-- data item dan merchant disalin saat order dibuat supaya riwayat order tidak ikut berubah
+ALTER TABLE order_items
+    ADD COLUMN item_name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN product_category VARCHAR(30) NOT NULL DEFAULT '',
+    ADD COLUMN unit_price NUMERIC(15,2) NOT NULL DEFAULT 0, -- harga per unit termasuk modifier
+    ADD COLUMN merchant_name TEXT NOT NULL DEFAULT '';
+
+UPDATE order_items oi
+SET item_name = i.name,
+    product_category = i.product_category,
+    unit_price = i.price + COALESCE((
+        SELECT SUM((o->>'priceDelta')::NUMERIC) FROM jsonb_array_elements(oi.options) o
+    ), 0),
+    merchant_name = m.name
+FROM items i, merchants m
+WHERE i.id = oi.item_id AND m.id = oi.merchant_id;
+
+-- menghapus item atau merchant tidak boleh ikut menghapus riwayat order
+ALTER TABLE order_items
+    DROP CONSTRAINT IF EXISTS fk_item_id,
+    DROP CONSTRAINT IF EXISTS fk_merchant_id;
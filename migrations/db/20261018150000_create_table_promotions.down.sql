@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS promotion_items;
+DROP TABLE IF EXISTS promotions;
@@ -0,0 +1,27 @@
+CREATE TABLE promotions (
+    id UUID PRIMARY KEY,
+    merchant_id UUID NOT NULL REFERENCES merchants (id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    scope VARCHAR(10) NOT NULL CHECK (scope IN ('merchant', 'category', 'items')),
+    product_category VARCHAR(30) NOT NULL DEFAULT '', -- hanya untuk scope category
+    discount_type VARCHAR(10) NOT NULL CHECK (discount_type IN ('percent', 'fixed')),
+    discount_value NUMERIC(15,2) NOT NULL CHECK (discount_value > 0),
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    -- jam harian dalam zona waktu merchant, NULL berarti sepanjang hari
+    daily_start_minute INTEGER CHECK (daily_start_minute BETWEEN 0 AND 1439),
+    daily_end_minute INTEGER CHECK (daily_end_minute BETWEEN 0 AND 1439),
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM clock_timestamp()) * 1e9)::BIGINT,
+
+    CHECK (ends_at > starts_at),
+    CHECK (discount_type <> 'percent' OR discount_value <= 100)
+);
+
+CREATE INDEX idx_promotions_merchant_id ON promotions (merchant_id, ends_at);
+
+CREATE TABLE promotion_items (
+    promotion_id UUID NOT NULL REFERENCES promotions (id) ON DELETE CASCADE,
+    item_id UUID NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+
+    PRIMARY KEY (promotion_id, item_id)
+);
@@ -0,0 +1,8 @@
+ALTER TABLE delivery_estimate
+    DROP COLUMN IF EXISTS promo_code_discount,
+    DROP COLUMN IF EXISTS promo_code,
+    DROP COLUMN IF EXISTS promotion_discount;
+
+DROP TABLE IF EXISTS promo_code_redemptions;
+DROP TABLE IF EXISTS promo_code_merchants;
+DROP TABLE IF EXISTS promo_codes;
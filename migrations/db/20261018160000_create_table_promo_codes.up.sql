@@ -0,0 +1,42 @@
+CREATE TABLE promo_codes (
+    id UUID PRIMARY KEY,
+    code VARCHAR(30) NOT NULL UNIQUE, -- selalu huruf besar
+    discount_type VARCHAR(10) NOT NULL CHECK (discount_type IN ('percent', 'fixed')),
+    discount_value NUMERIC(15,2) NOT NULL CHECK (discount_value > 0),
+    min_spend NUMERIC(15,2) NOT NULL DEFAULT 0,
+    max_discount NUMERIC(15,2), -- NULL berarti tanpa batas
+    usage_limit INTEGER CHECK (usage_limit > 0), -- NULL berarti tanpa batas
+    per_user_limit INTEGER CHECK (per_user_limit > 0),
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID NOT NULL,
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM clock_timestamp()) * 1e9)::BIGINT,
+
+    CHECK (ends_at > starts_at),
+    CHECK (discount_type <> 'percent' OR discount_value <= 100)
+);
+
+-- tanpa baris berarti berlaku untuk semua merchant
+CREATE TABLE promo_code_merchants (
+    promo_code_id UUID NOT NULL REFERENCES promo_codes (id) ON DELETE CASCADE,
+    merchant_id UUID NOT NULL REFERENCES merchants (id) ON DELETE CASCADE,
+
+    PRIMARY KEY (promo_code_id, merchant_id)
+);
+
+CREATE TABLE promo_code_redemptions (
+    id UUID PRIMARY KEY,
+    promo_code_id UUID NOT NULL REFERENCES promo_codes (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    order_id UUID NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    discount NUMERIC(15,2) NOT NULL,
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM clock_timestamp()) * 1e9)::BIGINT
+);
+
+CREATE INDEX idx_promo_code_redemptions_code_user ON promo_code_redemptions (promo_code_id, user_id);
+
+ALTER TABLE delivery_estimate
+    ADD COLUMN promotion_discount NUMERIC(15,2) NOT NULL DEFAULT 0,
+    ADD COLUMN promo_code VARCHAR(30) NOT NULL DEFAULT '',
+    ADD COLUMN promo_code_discount NUMERIC(15,2) NOT NULL DEFAULT 0;
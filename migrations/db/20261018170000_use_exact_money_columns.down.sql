@@ -0,0 +1,2 @@
+ALTER TABLE delivery_estimate ALTER COLUMN total_price TYPE DECIMAL(10, 2);
+ALTER TABLE orders ALTER COLUMN total_price TYPE DECIMAL;
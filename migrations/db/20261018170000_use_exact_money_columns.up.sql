@@ -0,0 +1,3 @@
+-- semua nilai uang disimpan dengan dua digit desimal
+ALTER TABLE orders ALTER COLUMN total_price TYPE NUMERIC(15,2) USING ROUND(total_price, 2);
+ALTER TABLE delivery_estimate ALTER COLUMN total_price TYPE NUMERIC(15,2);
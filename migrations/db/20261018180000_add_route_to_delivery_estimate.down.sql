@@ -0,0 +1,4 @@
+ALTER TABLE delivery_estimate
+    DROP COLUMN IF EXISTS departure_at,
+    DROP COLUMN IF EXISTS total_distance_km,
+    DROP COLUMN IF EXISTS route;
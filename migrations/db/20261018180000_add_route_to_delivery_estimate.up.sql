@@ -0,0 +1,9 @@
+-- rute pengantaran yang direncanakan saat estimasi
+ALTER TABLE delivery_estimate
+    ADD COLUMN route JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN total_distance_km DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN departure_at TIMESTAMP;
+
+UPDATE delivery_estimate SET departure_at = created_at;
+
+ALTER TABLE delivery_estimate ALTER COLUMN departure_at SET NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE delivery_estimate
+    DROP COLUMN IF EXISTS consumed_at,
+    DROP COLUMN IF EXISTS expires_at;
@@ -0,0 +1,9 @@
+-- estimasi hanya berlaku sebentar dan hanya bisa di-order sekali
+ALTER TABLE delivery_estimate
+    ADD COLUMN expires_at TIMESTAMP,
+    ADD COLUMN consumed_at TIMESTAMP;
+
+-- estimasi lama dianggap sudah kedaluwarsa
+UPDATE delivery_estimate SET expires_at = created_at + INTERVAL '15 minutes';
+
+ALTER TABLE delivery_estimate ALTER COLUMN expires_at SET NOT NULL;
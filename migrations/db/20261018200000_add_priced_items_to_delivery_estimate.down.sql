@@ -0,0 +1 @@
+ALTER TABLE delivery_estimate DROP COLUMN IF EXISTS priced_items;
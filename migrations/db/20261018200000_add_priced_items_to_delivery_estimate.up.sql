@@ -0,0 +1,2 @@
+-- order_items dengan harga saat estimasi, dipakai saat harga estimasi dikunci
+ALTER TABLE delivery_estimate ADD COLUMN priced_items JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,25 @@
+ALTER TABLE promotions ADD COLUMN discount_value NUMERIC(15,2);
+
+UPDATE promotions
+SET discount_value = CASE WHEN discount_type = 'percent' THEN discount_percent ELSE discount_amount END;
+
+ALTER TABLE promotions
+    DROP CONSTRAINT promotions_discount_check,
+    DROP COLUMN discount_percent,
+    DROP COLUMN discount_amount,
+    ALTER COLUMN discount_value SET NOT NULL,
+    ADD CHECK (discount_value > 0),
+    ADD CHECK (discount_type <> 'percent' OR discount_value <= 100);
+
+ALTER TABLE promo_codes ADD COLUMN discount_value NUMERIC(15,2);
+
+UPDATE promo_codes
+SET discount_value = CASE WHEN discount_type = 'percent' THEN discount_percent ELSE discount_amount END;
+
+ALTER TABLE promo_codes
+    DROP CONSTRAINT promo_codes_discount_check,
+    DROP COLUMN discount_percent,
+    DROP COLUMN discount_amount,
+    ALTER COLUMN discount_value SET NOT NULL,
+    ADD CHECK (discount_value > 0),
+    ADD CHECK (discount_type <> 'percent' OR discount_value <= 100);
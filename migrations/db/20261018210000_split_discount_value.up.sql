@@ -0,0 +1,29 @@
+-- persentase dan potongan tetap disimpan di kolom terpisah, potongan tetap memakai nilai uang yang eksak
+ALTER TABLE promotions
+    ADD COLUMN discount_percent NUMERIC(5,2) NOT NULL DEFAULT 0,
+    ADD COLUMN discount_amount NUMERIC(15,2) NOT NULL DEFAULT 0;
+
+UPDATE promotions SET discount_percent = discount_value WHERE discount_type = 'percent';
+UPDATE promotions SET discount_amount = discount_value WHERE discount_type = 'fixed';
+
+-- check constraint yang memakai discount_value ikut terhapus
+ALTER TABLE promotions
+    DROP COLUMN discount_value,
+    ADD CONSTRAINT promotions_discount_check CHECK (
+        (discount_type = 'percent' AND discount_percent > 0 AND discount_percent <= 100 AND discount_amount = 0)
+        OR (discount_type = 'fixed' AND discount_amount > 0 AND discount_percent = 0)
+    );
+
+ALTER TABLE promo_codes
+    ADD COLUMN discount_percent NUMERIC(5,2) NOT NULL DEFAULT 0,
+    ADD COLUMN discount_amount NUMERIC(15,2) NOT NULL DEFAULT 0;
+
+UPDATE promo_codes SET discount_percent = discount_value WHERE discount_type = 'percent';
+UPDATE promo_codes SET discount_amount = discount_value WHERE discount_type = 'fixed';
+
+ALTER TABLE promo_codes
+    DROP COLUMN discount_value,
+    ADD CONSTRAINT promo_codes_discount_check CHECK (
+        (discount_type = 'percent' AND discount_percent > 0 AND discount_percent <= 100 AND discount_amount = 0)
+        OR (discount_type = 'fixed' AND discount_amount > 0 AND discount_percent = 0)
+    );
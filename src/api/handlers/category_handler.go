@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"belimang/src/api/presenter"
+	"belimang/src/pkg/category"
+	"belimang/src/pkg/dtos"
+	"belimang/src/pkg/entities"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+var validateCategory = dtos.NewValidator()
+
+// GetCategories is handler/controller which lists the merchant and product categories
+// @Summary      List categories
+// @Description  Get every merchant and product category, e.g. to render filters
+// @Tags         Categories
+// @Produce      json
+// @Success      200   {object}  dtos.CategoryListResponse
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /api/v1/categories [get]
+func GetCategories(service category.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		result, err := service.FetchCategories()
+		if err != nil {
+			return c.Status(http.StatusInternalServerError).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.Status(http.StatusOK).JSON(result)
+	}
+}
+
+// CreateCategory is handler/controller which adds a category
+// @Summary      Create a category
+// @Description  Add a merchant or product category
+// @Tags         Categories
+// @Accept       json
+// @Produce      json
+// @Param        category  body      entities.RequestCategory  true  "Category"
+// @Success      201   {object}  dtos.CategoryResponse
+// @Failure      400   {object}  presenter.ValidationError
+// @Failure      409   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/categories [post]
+func CreateCategory(service category.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var requestBody entities.RequestCategory
+
+		if err := c.BodyParser(&requestBody); err != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse("invalid request body: " + err.Error()))
+		}
+
+		if errVal := validateCategory.Struct(requestBody); errVal != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ValidationErrorResponse(errVal))
+		}
+
+		result, err := service.CreateCategory(requestBody)
+		if err != nil {
+			return c.Status(categoryErrorStatus(err)).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.Status(http.StatusCreated).JSON(result)
+	}
+}
+
+// UpdateCategory is handler/controller which renames a category
+// @Summary      Rename a category
+// @Description  Rename a category, merchants and items using it are moved to the new name
+// @Tags         Categories
+// @Accept       json
+// @Produce      json
+// @Param        categoryId  path      string                          true  "Category ID (UUID)"
+// @Param        category    body      entities.UpdateCategoryRequest  true  "Category"
+// @Success      200   {object}  dtos.CategoryResponse
+// @Failure      400   {object}  presenter.ValidationError
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      409   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/categories/{categoryId} [patch]
+func UpdateCategory(service category.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var requestBody entities.UpdateCategoryRequest
+
+		categoryIdUUID, err := uuid.Parse(c.Params("categoryId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(category.ErrCategoryNotFound.Error()))
+		}
+
+		if err := c.BodyParser(&requestBody); err != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse("invalid request body: " + err.Error()))
+		}
+
+		if errVal := validateCategory.Struct(requestBody); errVal != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ValidationErrorResponse(errVal))
+		}
+
+		result, err := service.UpdateCategory(categoryIdUUID, requestBody)
+		if err != nil {
+			return c.Status(categoryErrorStatus(err)).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.Status(http.StatusOK).JSON(result)
+	}
+}
+
+// DeleteCategory is handler/controller which deletes an unused category
+// @Summary      Delete a category
+// @Description  Delete a category that no merchant or item uses anymore
+// @Tags         Categories
+// @Produce      json
+// @Param        categoryId  path  string  true  "Category ID (UUID)"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      409   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/categories/{categoryId} [delete]
+func DeleteCategory(service category.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		categoryIdUUID, err := uuid.Parse(c.Params("categoryId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(category.ErrCategoryNotFound.Error()))
+		}
+
+		if err := service.RemoveCategory(categoryIdUUID); err != nil {
+			return c.Status(categoryErrorStatus(err)).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.JSON(
+			map[string]interface{}{
+				"categoryId": categoryIdUUID,
+			},
+		)
+	}
+}
+
+// categoryErrorStatus maps category service errors to HTTP status codes
+func categoryErrorStatus(err error) int {
+	switch err {
+	case category.ErrCategoryNotFound:
+		return http.StatusNotFound
+	case category.ErrCategoryExists, category.ErrCategoryInUse:
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
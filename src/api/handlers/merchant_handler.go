@@ -3,19 +3,23 @@ package handlers
 import (
 	"belimang/src/api/presenter"
 
+	"belimang/src/pkg/dtos"
 	"belimang/src/pkg/entities"
 	"belimang/src/pkg/merchant"
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
 
-// validator instance
-var validateMerchant = validator.New()
-var validateItems = validator.New()
-
 // CreateMerchant is handler/controller which creates merchant
 // @Summary      Create a new merchant
 // @Description  Add a new merchant to the collection
@@ -24,11 +28,18 @@ var validateItems = validator.New()
 // @Produce      json
 // @Param        merchant  body      entities.RequestMerchant  true  "Merchant object"
 // @Success      200   {object}  map[string]interface{}
-// @Failure      400   {object}  map[string]interface{}
+// @Failure      400   {object}  presenter.ValidationError
 // @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
 // @Router       /api/v1/admin/merchants [post]
 func CreateMerchant(service merchant.Service) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
 		var requestBody entities.RequestMerchant
 
 		if err := c.BodyParser(&requestBody); err != nil {
@@ -36,9 +47,8 @@ func CreateMerchant(service merchant.Service) fiber.Handler {
 				JSON(presenter.ErrorResponse("invalid request body: " + err.Error()))
 		}
 
-		if errVal := validateMerchant.Struct(requestBody); errVal != nil {
-			return c.Status(http.StatusBadRequest).
-				JSON(presenter.ErrorResponse(errVal.Error()))
+		if errVal := service.Validate(requestBody); errVal != nil {
+			return validationFailed(c, errVal)
 		}
 
 		// Validate the request body
@@ -49,8 +59,14 @@ func CreateMerchant(service merchant.Service) fiber.Handler {
 			Long:             requestBody.Location.Long,
 			MerchantCategory: requestBody.MerchantCategory,
 		}
+		if requestBody.MaxDeliveryKm != nil {
+			new_data.MaxDeliveryKm = *requestBody.MaxDeliveryKm
+		}
+		if requestBody.MinOrderValue != nil {
+			new_data.MinOrderValue = *requestBody.MinOrderValue
+		}
 
-		result, err := service.InsertMerchant(&new_data)
+		result, err := service.InsertMerchant(actor, &new_data)
 		if err != nil {
 			c.Status(http.StatusInternalServerError)
 			return c.JSON(presenter.ErrorResponse(err.Error()))
@@ -72,27 +88,36 @@ func CreateMerchant(service merchant.Service) fiber.Handler {
 // @Param        merchantId  path      string                true  "Merchant ID (UUID)"
 // @Param        item        body      entities.RequestItems true  "New Item"
 // @Success      200   {object}  map[string]interface{}
-// @Failure      400   {object}  map[string]interface{}
+// @Failure      400   {object}  presenter.ValidationError
+// @Failure      404   {object}  map[string]interface{}
 // @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
 // @Router       /api/v1/admin/merchants/{merchantId}/items [post]
 func CreateMerchantItems(service merchant.Service) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
 		var requestBody entities.RequestItems
 		merchantId := c.Params("merchantId")
-		if err := c.BodyParser(&requestBody); err != nil {
-			return c.Status(http.StatusBadRequest).
-				JSON(presenter.ErrorResponse("invalid request body: " + err.Error()))
+
+		merchantIdUUID, err := uuid.Parse(merchantId)
+		if err != nil {
+			// merchantId yang tidak valid dianggap tidak ditemukan
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrMerchantNotFound.Error()))
 		}
 
-		if errVal := validateItems.Struct(requestBody); errVal != nil {
+		if err := c.BodyParser(&requestBody); err != nil {
 			return c.Status(http.StatusBadRequest).
-				JSON(presenter.ErrorResponse(errVal.Error()))
+				JSON(presenter.ErrorResponse("invalid request body: " + err.Error()))
 		}
 
-		merchantIdUUID, err := uuid.Parse(merchantId)
-		if err != nil {
-			// handle the error
-			return c.Status(http.StatusBadRequest).JSON(presenter.ErrorResponse(err.Error()))
+		if errVal := service.Validate(requestBody); errVal != nil {
+			return validationFailed(c, errVal)
 		}
 
 		new_data := entities.Items{
@@ -101,11 +126,17 @@ func CreateMerchantItems(service merchant.Service) fiber.Handler {
 			Price:           requestBody.Price,
 			ProductCategory: requestBody.ProductCategory,
 			MerchantID:      merchantIdUUID,
+			Stock:           requestBody.Stock,
+			IsAvailable:     true,
+			Tags:            entities.ToItemTags(requestBody.Tags),
+		}
+		if requestBody.IsAvailable != nil {
+			new_data.IsAvailable = *requestBody.IsAvailable
 		}
 
-		result, err := service.CreateItems(&new_data, merchantIdUUID)
+		result, err := service.CreateItems(actor, &new_data, merchantIdUUID)
 		if err != nil {
-			c.Status(http.StatusInternalServerError)
+			c.Status(merchantErrorStatus(err))
 			return c.JSON(presenter.ErrorResponse(err.Error()))
 		}
 		return c.JSON(
@@ -115,3 +146,902 @@ func CreateMerchantItems(service merchant.Service) fiber.Handler {
 		)
 	}
 }
+
+// GetMerchants is handler/controller which lists merchants
+// @Summary      List merchants
+// @Description  Get merchants with optional filters, sorting and pagination
+// @Tags         Merchants
+// @Accept       json
+// @Produce      json
+// @Param        merchantId        query  string  false "Merchant ID"
+// @Param        name              query  string  false "Merchant name (substring, case insensitive)"
+// @Param        merchantCategory  query  string  false "Merchant category"
+// @Param        createdAt         query  string  false "Sort by created time (asc|desc)"
+// @Param        limit             query  int     false "Limit results (default: 5)"
+// @Param        offset            query  int     false "Pagination offset (default: 0)"
+// @Success      200   {object}  dtos.MerchantListResponse
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/merchants [get]
+func GetMerchants(service merchant.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		limit, _ := strconv.Atoi(c.Query("limit", "5"))
+		offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+		if limit <= 0 {
+			limit = 5
+		}
+		if offset < 0 {
+			offset = 0
+		}
+
+		result, err := service.FetchMerchants(actor, map[string]interface{}{
+			"limit":            limit,
+			"offset":           offset,
+			"merchantId":       c.Query("merchantId"),
+			"name":             c.Query("name"),
+			"merchantCategory": c.Query("merchantCategory"),
+			"createdAt":        c.Query("createdAt"),
+		})
+		if err != nil {
+			return c.Status(http.StatusInternalServerError).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		return c.Status(http.StatusOK).JSON(result)
+	}
+}
+
+// GetMerchantItems is handler/controller which lists the items of a merchant
+// @Summary      List items of a merchant
+// @Description  Get items under a specific merchant with optional filters, sorting and pagination
+// @Tags         Merchants
+// @Accept       json
+// @Produce      json
+// @Param        merchantId       path   string  true  "Merchant ID (UUID)"
+// @Param        itemId           query  string  false "Item ID"
+// @Param        name             query  string  false "Item name (substring, case insensitive)"
+// @Param        productCategory  query  string  false "Product category"
+// @Param        createdAt        query  string  false "Sort by created time (asc|desc)"
+// @Param        limit            query  int     false "Limit results (default: 5)"
+// @Param        offset           query  int     false "Pagination offset (default: 0)"
+// @Success      200   {object}  dtos.ItemListResponse
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/merchants/{merchantId}/items [get]
+func GetMerchantItems(service merchant.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		merchantIdUUID, err := uuid.Parse(c.Params("merchantId"))
+		if err != nil {
+			// merchantId yang tidak valid dianggap tidak ditemukan
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrMerchantNotFound.Error()))
+		}
+
+		limit, _ := strconv.Atoi(c.Query("limit", "5"))
+		offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+		if limit <= 0 {
+			limit = 5
+		}
+		if offset < 0 {
+			offset = 0
+		}
+
+		result, err := service.FetchItems(actor, merchantIdUUID, map[string]interface{}{
+			"limit":           limit,
+			"offset":          offset,
+			"itemId":          c.Query("itemId"),
+			"name":            c.Query("name"),
+			"productCategory": c.Query("productCategory"),
+			"createdAt":       c.Query("createdAt"),
+		})
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if err == merchant.ErrMerchantNotFound {
+				statusCode = http.StatusNotFound
+			}
+			return c.Status(statusCode).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		return c.Status(http.StatusOK).JSON(result)
+	}
+}
+
+// UpdateMerchant is handler/controller which updates a merchant
+// @Summary      Update a merchant
+// @Description  Update the provided fields of a merchant
+// @Tags         Merchants
+// @Accept       json
+// @Produce      json
+// @Param        merchantId  path      string                          true  "Merchant ID (UUID)"
+// @Param        merchant    body      entities.UpdateMerchantRequest  true  "Fields to update"
+// @Success      200   {object}  dtos.MerchantResponse
+// @Failure      400   {object}  presenter.ValidationError
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/merchants/{merchantId} [patch]
+func UpdateMerchant(service merchant.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		var requestBody entities.UpdateMerchantRequest
+
+		merchantIdUUID, err := uuid.Parse(c.Params("merchantId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrMerchantNotFound.Error()))
+		}
+
+		if err := c.BodyParser(&requestBody); err != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse("invalid request body: " + err.Error()))
+		}
+
+		if errVal := service.Validate(requestBody); errVal != nil {
+			return validationFailed(c, errVal)
+		}
+
+		result, err := service.UpdateMerchant(actor, merchantIdUUID, requestBody)
+		if err != nil {
+			return c.Status(merchantErrorStatus(err)).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.Status(http.StatusOK).JSON(dtos.ToMerchantResponse(*result))
+	}
+}
+
+// DeleteMerchant is handler/controller which soft deletes a merchant
+// @Summary      Delete a merchant
+// @Description  Soft delete a merchant so it no longer shows up for users
+// @Tags         Merchants
+// @Produce      json
+// @Param        merchantId  path      string  true  "Merchant ID (UUID)"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/merchants/{merchantId} [delete]
+func DeleteMerchant(service merchant.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		merchantIdUUID, err := uuid.Parse(c.Params("merchantId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrMerchantNotFound.Error()))
+		}
+
+		if err := service.RemoveMerchant(actor, merchantIdUUID); err != nil {
+			return c.Status(merchantErrorStatus(err)).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.JSON(
+			map[string]interface{}{
+				"merchantId": merchantIdUUID,
+			},
+		)
+	}
+}
+
+// UpdateMerchantItems is handler/controller which updates an item of a merchant
+// @Summary      Update an item of a merchant
+// @Description  Update the provided fields of an item under a specific merchant
+// @Tags         Merchants
+// @Accept       json
+// @Produce      json
+// @Param        merchantId  path      string                       true  "Merchant ID (UUID)"
+// @Param        itemId      path      string                       true  "Item ID (UUID)"
+// @Param        item        body      entities.UpdateItemsRequest  true  "Fields to update"
+// @Success      200   {object}  dtos.ItemResponse
+// @Failure      400   {object}  presenter.ValidationError
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/merchants/{merchantId}/items/{itemId} [patch]
+func UpdateMerchantItems(service merchant.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		var requestBody entities.UpdateItemsRequest
+
+		merchantIdUUID, err := uuid.Parse(c.Params("merchantId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrMerchantNotFound.Error()))
+		}
+		itemIdUUID, err := uuid.Parse(c.Params("itemId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrItemNotFound.Error()))
+		}
+
+		if err := c.BodyParser(&requestBody); err != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse("invalid request body: " + err.Error()))
+		}
+
+		if errVal := service.Validate(requestBody); errVal != nil {
+			return validationFailed(c, errVal)
+		}
+
+		result, err := service.UpdateItems(actor, merchantIdUUID, itemIdUUID, requestBody)
+		if err != nil {
+			return c.Status(merchantErrorStatus(err)).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.Status(http.StatusOK).JSON(dtos.ToItemResponse(*result))
+	}
+}
+
+// DeleteMerchantItems is handler/controller which soft deletes an item of a merchant
+// @Summary      Delete an item of a merchant
+// @Description  Soft delete an item so it is taken off the menu
+// @Tags         Merchants
+// @Produce      json
+// @Param        merchantId  path      string  true  "Merchant ID (UUID)"
+// @Param        itemId      path      string  true  "Item ID (UUID)"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/merchants/{merchantId}/items/{itemId} [delete]
+func DeleteMerchantItems(service merchant.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		merchantIdUUID, err := uuid.Parse(c.Params("merchantId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrMerchantNotFound.Error()))
+		}
+		itemIdUUID, err := uuid.Parse(c.Params("itemId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrItemNotFound.Error()))
+		}
+
+		if err := service.RemoveItems(actor, merchantIdUUID, itemIdUUID); err != nil {
+			return c.Status(merchantErrorStatus(err)).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.JSON(
+			map[string]interface{}{
+				"itemsId": itemIdUUID,
+			},
+		)
+	}
+}
+
+// ImportMerchants is handler/controller which bulk imports merchants with their items
+// @Summary      Import merchants
+// @Description  Bulk import merchants and their items from a csv or ndjson file. All rows are validated and inserted in one transaction.
+// @Tags         Merchants
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file    formData  file    true   "CSV or NDJSON file"
+// @Param        format  query     string  false  "File format (csv|ndjson), detected from the file extension when empty"
+// @Param        dryRun  query     bool    false  "Validate only without writing"
+// @Success      200   {object}  presenter.ImportReport
+// @Success      201   {object}  presenter.ImportReport
+// @Failure      400   {object}  presenter.ImportReport
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/merchants/import [post]
+func ImportMerchants(service merchant.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse("file not found in request"))
+		}
+
+		format := strings.ToLower(c.Query("format"))
+		if format == "" {
+			switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+			case ".csv":
+				format = merchant.ImportFormatCSV
+			case ".ndjson", ".jsonl":
+				format = merchant.ImportFormatNDJSON
+			}
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse("failed to open file: " + err.Error()))
+		}
+		defer file.Close()
+
+		result, err := service.ImportMerchants(actor, file, format, c.QueryBool("dryRun"))
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if errors.Is(err, merchant.ErrUnsupportedImportFormat) || errors.Is(err, merchant.ErrInvalidImportFile) {
+				statusCode = http.StatusBadRequest
+			}
+			return c.Status(statusCode).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		report := presenter.ImportReportResponse(result)
+		switch {
+		case len(result.Errors) > 0:
+			return c.Status(http.StatusBadRequest).JSON(report)
+		case result.DryRun:
+			return c.Status(http.StatusOK).JSON(report)
+		default:
+			return c.Status(http.StatusCreated).JSON(report)
+		}
+	}
+}
+
+// ExportMerchants is handler/controller which exports the merchant catalog
+// @Summary      Export merchants
+// @Description  Stream all merchants with their items as csv, ndjson or a GeoJSON FeatureCollection of merchant points.
+// @Description  When the export fails after streaming started the connection is closed without ending the chunked body.
+// @Tags         Merchants
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Produce      application/geo+json
+// @Param        format  query  string  false  "Export format (csv|ndjson|geojson), default csv"
+// @Success      200   {file}    file
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/merchants/export [get]
+func ExportMerchants(service merchant.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		format := strings.ToLower(c.Query("format", merchant.ExportFormatCSV))
+		contentType, err := merchant.ExportContentType(format)
+		if err != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		// format dan query diperiksa sebelum streaming, selama status masih bisa diubah
+		export, err := service.ExportCatalog(actor, format)
+		if err != nil {
+			return c.Status(http.StatusInternalServerError).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		c.Set(fiber.HeaderContentType, contentType)
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="merchants.%s"`, format))
+
+		conn := c.Context().Conn()
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			defer export.Close()
+			if err := export.Stream(w); err != nil {
+				log.Printf("Failed to export merchants: %v", err)
+				// status 200 sudah terkirim, koneksi diputus sebelum chunk penutup
+				// supaya klien melihat body yang terpotong sebagai error
+				conn.Close()
+			}
+		})
+		return nil
+	}
+}
+
+// GetMerchantOpeningHours is handler/controller which shows the schedule of a merchant
+// @Summary      Get merchant opening hours
+// @Description  Get the weekly opening hours and holiday closures of a merchant
+// @Tags         Merchants
+// @Produce      json
+// @Param        merchantId  path      string  true  "Merchant ID (UUID)"
+// @Success      200   {object}  dtos.OpeningHoursResponse
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/merchants/{merchantId}/hours [get]
+func GetMerchantOpeningHours(service merchant.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		merchantIdUUID, err := uuid.Parse(c.Params("merchantId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrMerchantNotFound.Error()))
+		}
+
+		result, err := service.FetchOpeningHours(actor, merchantIdUUID)
+		if err != nil {
+			return c.Status(merchantErrorStatus(err)).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.Status(http.StatusOK).JSON(result)
+	}
+}
+
+// UpdateMerchantOpeningHours is handler/controller which replaces the weekly schedule of a merchant
+// @Summary      Replace merchant opening hours
+// @Description  Replace the weekly opening hours of a merchant. Times are HH:MM in the merchant timezone, dayOfWeek 0 is Sunday, a close before open runs past midnight. An empty list means always open.
+// @Tags         Merchants
+// @Accept       json
+// @Produce      json
+// @Param        merchantId  path      string                        true  "Merchant ID (UUID)"
+// @Param        hours       body      entities.RequestOpeningHours  true  "Weekly schedule"
+// @Success      200   {object}  dtos.OpeningHoursResponse
+// @Failure      400   {object}  presenter.ValidationError
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/merchants/{merchantId}/hours [put]
+func UpdateMerchantOpeningHours(service merchant.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		var requestBody entities.RequestOpeningHours
+
+		merchantIdUUID, err := uuid.Parse(c.Params("merchantId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrMerchantNotFound.Error()))
+		}
+
+		if err := c.BodyParser(&requestBody); err != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse("invalid request body: " + err.Error()))
+		}
+
+		if errVal := service.Validate(requestBody); errVal != nil {
+			return validationFailed(c, errVal)
+		}
+
+		result, err := service.UpdateOpeningHours(actor, merchantIdUUID, requestBody)
+		if err != nil {
+			return c.Status(merchantErrorStatus(err)).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.Status(http.StatusOK).JSON(result)
+	}
+}
+
+// CreateMerchantClosure is handler/controller which closes a merchant for a range of dates
+// @Summary      Add a merchant closure
+// @Description  Close a merchant for a range of dates (inclusive, in the merchant timezone), e.g. for holidays
+// @Tags         Merchants
+// @Accept       json
+// @Produce      json
+// @Param        merchantId  path      string                   true  "Merchant ID (UUID)"
+// @Param        closure     body      entities.RequestClosure  true  "Closure"
+// @Success      201   {object}  dtos.ClosureResponse
+// @Failure      400   {object}  presenter.ValidationError
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/merchants/{merchantId}/closures [post]
+func CreateMerchantClosure(service merchant.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		var requestBody entities.RequestClosure
+
+		merchantIdUUID, err := uuid.Parse(c.Params("merchantId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrMerchantNotFound.Error()))
+		}
+
+		if err := c.BodyParser(&requestBody); err != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse("invalid request body: " + err.Error()))
+		}
+
+		if errVal := service.Validate(requestBody); errVal != nil {
+			return validationFailed(c, errVal)
+		}
+
+		result, err := service.AddClosure(actor, merchantIdUUID, requestBody)
+		if err != nil {
+			return c.Status(merchantErrorStatus(err)).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.Status(http.StatusCreated).JSON(result)
+	}
+}
+
+// DeleteMerchantClosure is handler/controller which removes a closure of a merchant
+// @Summary      Delete a merchant closure
+// @Description  Remove a closure so the merchant follows its weekly schedule again
+// @Tags         Merchants
+// @Produce      json
+// @Param        merchantId  path      string  true  "Merchant ID (UUID)"
+// @Param        closureId   path      string  true  "Closure ID (UUID)"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/merchants/{merchantId}/closures/{closureId} [delete]
+func DeleteMerchantClosure(service merchant.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		merchantIdUUID, err := uuid.Parse(c.Params("merchantId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrMerchantNotFound.Error()))
+		}
+
+		closureIdUUID, err := uuid.Parse(c.Params("closureId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrClosureNotFound.Error()))
+		}
+
+		if err := service.RemoveClosure(actor, merchantIdUUID, closureIdUUID); err != nil {
+			return c.Status(merchantErrorStatus(err)).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.JSON(
+			map[string]interface{}{
+				"closureId": closureIdUUID,
+			},
+		)
+	}
+}
+
+// GetItemModifiers is handler/controller which lists the modifier groups of an item
+// @Summary      List item modifiers
+// @Description  List the modifier groups (e.g. size, add-ons) of an item with their options
+// @Tags         Merchants
+// @Produce      json
+// @Param        merchantId  path      string  true  "Merchant ID (UUID)"
+// @Param        itemId      path      string  true  "Item ID (UUID)"
+// @Success      200   {array}   dtos.ModifierGroupResponse
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/merchants/{merchantId}/items/{itemId}/modifiers [get]
+func GetItemModifiers(service merchant.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		merchantIdUUID, err := uuid.Parse(c.Params("merchantId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrMerchantNotFound.Error()))
+		}
+		itemIdUUID, err := uuid.Parse(c.Params("itemId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrItemNotFound.Error()))
+		}
+
+		result, err := service.FetchModifierGroups(actor, merchantIdUUID, itemIdUUID)
+		if err != nil {
+			return c.Status(merchantErrorStatus(err)).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.Status(http.StatusOK).JSON(result)
+	}
+}
+
+// CreateItemModifier is handler/controller which adds a modifier group to an item
+// @Summary      Add an item modifier group
+// @Description  Add a modifier group with its options to an item. A required group needs at least one selection, priceDelta is added to the item price per selected option.
+// @Tags         Merchants
+// @Accept       json
+// @Produce      json
+// @Param        merchantId  path      string                         true  "Merchant ID (UUID)"
+// @Param        itemId      path      string                         true  "Item ID (UUID)"
+// @Param        modifier    body      entities.RequestModifierGroup  true  "Modifier group"
+// @Success      201   {object}  dtos.ModifierGroupResponse
+// @Failure      400   {object}  presenter.ValidationError
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/merchants/{merchantId}/items/{itemId}/modifiers [post]
+func CreateItemModifier(service merchant.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		var requestBody entities.RequestModifierGroup
+
+		merchantIdUUID, err := uuid.Parse(c.Params("merchantId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrMerchantNotFound.Error()))
+		}
+		itemIdUUID, err := uuid.Parse(c.Params("itemId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrItemNotFound.Error()))
+		}
+
+		if err := c.BodyParser(&requestBody); err != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse("invalid request body: " + err.Error()))
+		}
+
+		if errVal := service.Validate(requestBody); errVal != nil {
+			return validationFailed(c, errVal)
+		}
+
+		result, err := service.AddModifierGroup(actor, merchantIdUUID, itemIdUUID, requestBody)
+		if err != nil {
+			return c.Status(merchantErrorStatus(err)).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.Status(http.StatusCreated).JSON(result)
+	}
+}
+
+// DeleteItemModifier is handler/controller which removes a modifier group of an item
+// @Summary      Delete an item modifier group
+// @Description  Remove a modifier group and its options from an item, past orders keep their selections
+// @Tags         Merchants
+// @Produce      json
+// @Param        merchantId       path      string  true  "Merchant ID (UUID)"
+// @Param        itemId           path      string  true  "Item ID (UUID)"
+// @Param        modifierGroupId  path      string  true  "Modifier group ID (UUID)"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/merchants/{merchantId}/items/{itemId}/modifiers/{modifierGroupId} [delete]
+func DeleteItemModifier(service merchant.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		merchantIdUUID, err := uuid.Parse(c.Params("merchantId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrMerchantNotFound.Error()))
+		}
+		itemIdUUID, err := uuid.Parse(c.Params("itemId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrItemNotFound.Error()))
+		}
+		groupIdUUID, err := uuid.Parse(c.Params("modifierGroupId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrModifierGroupNotFound.Error()))
+		}
+
+		if err := service.RemoveModifierGroup(actor, merchantIdUUID, itemIdUUID, groupIdUUID); err != nil {
+			return c.Status(merchantErrorStatus(err)).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.JSON(
+			map[string]interface{}{
+				"modifierGroupId": groupIdUUID,
+			},
+		)
+	}
+}
+
+// GetMerchantPromotions is handler/controller which lists the promotions of a merchant
+// @Summary      List merchant promotions
+// @Description  List every promotion of a merchant, including expired and upcoming ones
+// @Tags         Merchants
+// @Produce      json
+// @Param        merchantId  path      string  true  "Merchant ID (UUID)"
+// @Success      200   {array}   dtos.PromotionResponse
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/merchants/{merchantId}/promotions [get]
+func GetMerchantPromotions(service merchant.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		merchantIdUUID, err := uuid.Parse(c.Params("merchantId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrMerchantNotFound.Error()))
+		}
+
+		result, err := service.FetchPromotions(actor, merchantIdUUID)
+		if err != nil {
+			return c.Status(merchantErrorStatus(err)).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.Status(http.StatusOK).JSON(result)
+	}
+}
+
+// CreateMerchantPromotion is handler/controller which schedules a promotion for a merchant
+// @Summary      Create a merchant promotion
+// @Description  Discount the whole merchant, a product category or specific items by a percentage (discountPercent) or a fixed amount (discountAmount) between startsAt and endsAt (RFC3339). dailyStart and dailyEnd (HH:MM, merchant timezone) limit the discount to a daily window. Promotions do not stack, the biggest discount wins.
+// @Tags         Merchants
+// @Accept       json
+// @Produce      json
+// @Param        merchantId  path      string                     true  "Merchant ID (UUID)"
+// @Param        promotion   body      entities.RequestPromotion  true  "Promotion"
+// @Success      201   {object}  dtos.PromotionResponse
+// @Failure      400   {object}  presenter.ValidationError
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/merchants/{merchantId}/promotions [post]
+func CreateMerchantPromotion(service merchant.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		var requestBody entities.RequestPromotion
+
+		merchantIdUUID, err := uuid.Parse(c.Params("merchantId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrMerchantNotFound.Error()))
+		}
+
+		if err := c.BodyParser(&requestBody); err != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse("invalid request body: " + err.Error()))
+		}
+
+		if errVal := service.Validate(requestBody); errVal != nil {
+			return validationFailed(c, errVal)
+		}
+
+		result, err := service.AddPromotion(actor, merchantIdUUID, requestBody)
+		if err != nil {
+			return c.Status(merchantErrorStatus(err)).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.Status(http.StatusCreated).JSON(result)
+	}
+}
+
+// DeleteMerchantPromotion is handler/controller which removes a promotion of a merchant
+// @Summary      Delete a merchant promotion
+// @Description  Remove a promotion, orders already placed keep their discounted prices
+// @Tags         Merchants
+// @Produce      json
+// @Param        merchantId   path      string  true  "Merchant ID (UUID)"
+// @Param        promotionId  path      string  true  "Promotion ID (UUID)"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/merchants/{merchantId}/promotions/{promotionId} [delete]
+func DeleteMerchantPromotion(service merchant.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		merchantIdUUID, err := uuid.Parse(c.Params("merchantId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrMerchantNotFound.Error()))
+		}
+		promotionIdUUID, err := uuid.Parse(c.Params("promotionId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(merchant.ErrPromotionNotFound.Error()))
+		}
+
+		if err := service.RemovePromotion(actor, merchantIdUUID, promotionIdUUID); err != nil {
+			return c.Status(merchantErrorStatus(err)).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.JSON(
+			map[string]interface{}{
+				"promotionId": promotionIdUUID,
+			},
+		)
+	}
+}
+
+// merchantActor builds the acting admin from the claims stored by the JWT middleware
+func merchantActor(c *fiber.Ctx) (merchant.Actor, error) {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return merchant.Actor{}, errors.New("unauthorized")
+	}
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return merchant.Actor{}, errors.New("invalid token claims")
+	}
+	return merchant.Actor{
+		UserID:     id,
+		SuperAdmin: c.Locals("role") == entities.RoleSuperAdmin,
+	}, nil
+}
+
+// validationFailed answers 400 with the invalid fields, or 500 when the request could not be
+// validated at all, e.g. because the categories could not be loaded
+func validationFailed(c *fiber.Ctx, err error) error {
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return c.Status(http.StatusInternalServerError).
+			JSON(presenter.ErrorResponse(err.Error()))
+	}
+	return c.Status(http.StatusBadRequest).
+		JSON(presenter.ValidationErrorResponse(err))
+}
+
+// merchantErrorStatus maps merchant service errors to HTTP status codes
+func merchantErrorStatus(err error) int {
+	switch err {
+	case merchant.ErrMerchantNotFound, merchant.ErrItemNotFound, merchant.ErrClosureNotFound, merchant.ErrModifierGroupNotFound, merchant.ErrPromotionNotFound:
+		return http.StatusNotFound
+	case merchant.ErrInvalidClosure, merchant.ErrInvalidOpeningHours, merchant.ErrInvalidModifierGroup, merchant.ErrInvalidPromotion:
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"belimang/src/api/presenter"
+	"belimang/src/pkg/dtos"
+	"belimang/src/pkg/entities"
+	"belimang/src/pkg/merchant"
+	"belimang/src/pkg/promocode"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+var validatePromoCode = dtos.NewValidator()
+
+// CreatePromoCode is handler/controller which creates a promo code
+// @Summary      Create a promo code
+// @Description  Create a code users can enter at estimate. Leaving merchantIds empty makes the code valid for every merchant, which only superadmins may do.
+// @Tags         Promo Codes
+// @Accept       json
+// @Produce      json
+// @Param        promoCode  body      entities.RequestPromoCode  true  "Promo code"
+// @Success      201   {object}  dtos.PromoCodeResponse
+// @Failure      400   {object}  presenter.ValidationError
+// @Failure      409   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/promo-codes [post]
+func CreatePromoCode(service promocode.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		var requestBody entities.RequestPromoCode
+
+		if err := c.BodyParser(&requestBody); err != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse("invalid request body: " + err.Error()))
+		}
+
+		if errVal := validatePromoCode.Struct(requestBody); errVal != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ValidationErrorResponse(errVal))
+		}
+
+		result, err := service.CreatePromoCode(actor, requestBody)
+		if err != nil {
+			return c.Status(promoCodeErrorStatus(err)).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.Status(http.StatusCreated).JSON(result)
+	}
+}
+
+// GetPromoCodes is handler/controller which lists promo codes
+// @Summary      List promo codes
+// @Description  Get the promo codes created by the admin with their usage, superadmins see every code
+// @Tags         Promo Codes
+// @Produce      json
+// @Param        isActive  query  bool  false  "Filter on active state"
+// @Param        limit     query  int   false  "Limit results (default: 5)"
+// @Param        offset    query  int   false  "Pagination offset (default: 0)"
+// @Success      200   {object}  dtos.PromoCodeListResponse
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/promo-codes [get]
+func GetPromoCodes(service promocode.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		limit, offset := reviewPagination(c)
+		params := map[string]interface{}{
+			"limit":  limit,
+			"offset": offset,
+		}
+		if v, err := strconv.ParseBool(c.Query("isActive")); err == nil {
+			params["isActive"] = v
+		}
+
+		result, err := service.FetchPromoCodes(actor, params)
+		if err != nil {
+			return c.Status(http.StatusInternalServerError).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.Status(http.StatusOK).JSON(result)
+	}
+}
+
+// DeactivatePromoCode is handler/controller which deactivates a promo code
+// @Summary      Deactivate a promo code
+// @Description  Stop a promo code from being used, estimates holding the code can no longer be ordered with it
+// @Tags         Promo Codes
+// @Produce      json
+// @Param        promoCodeId  path      string  true  "Promo code ID (UUID)"
+// @Success      200   {object}  dtos.PromoCodeResponse
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/promo-codes/{promoCodeId}/deactivate [patch]
+func DeactivatePromoCode(service promocode.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		promoCodeIdUUID, err := uuid.Parse(c.Params("promoCodeId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(promocode.ErrPromoCodeNotFound.Error()))
+		}
+
+		result, err := service.DeactivatePromoCode(actor, promoCodeIdUUID)
+		if err != nil {
+			return c.Status(promoCodeErrorStatus(err)).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.Status(http.StatusOK).JSON(result)
+	}
+}
+
+// promoCodeErrorStatus maps promo code service errors to HTTP status codes
+func promoCodeErrorStatus(err error) int {
+	switch err {
+	case promocode.ErrPromoCodeNotFound, merchant.ErrMerchantNotFound:
+		return http.StatusNotFound
+	case promocode.ErrInvalidPromoCode, promocode.ErrMerchantsRequired:
+		return http.StatusBadRequest
+	case promocode.ErrPromoCodeExists:
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"belimang/src/pkg/dtos"
 	"belimang/src/pkg/entities"
 	"belimang/src/pkg/purchase"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -21,6 +24,9 @@ import (
 // @Param        merchantId  query  string  false "Merchant ID"
 // @Param        name  query  string  false "Merchant name"
 // @Param        merchantCategory  query  string  false "Merchant category"
+// @Param        openNow  query  bool    false "Only merchants that are open right now"
+// @Param        sortBy   query  string  false "Sort order (distance|rating), default distance"
+// @Param        tags     query  string  false "Comma separated item tags, only items carrying every tag are listed"
 // @Param        limit   query  int    false "Limit results (default: 5)"
 // @Param        offset  query  int    false "Pagination offset (default: 0)"
 // @Security     BearerAuth
@@ -55,6 +61,11 @@ func FindNearbyMerchant(service purchase.Service) fiber.Handler {
 		merchantId := c.Query("merchantId")
 		name := c.Query("name")
 		merchantCategory := c.Query("merchantCategory")
+		openNow := c.QueryBool("openNow")
+		var tags []string
+		if v := c.Query("tags"); v != "" {
+			tags = entities.NormalizeTags(strings.Split(v, ","))
+		}
 
 		limit, _ := strconv.Atoi(limitParam)
 		offset, _ := strconv.Atoi(offsetParam)
@@ -69,6 +80,9 @@ func FindNearbyMerchant(service purchase.Service) fiber.Handler {
 			"merchantId":       merchantId,
 			"name":             name,
 			"merchantCategory": merchantCategory,
+			"openNow":          openNow,
+			"sortBy":           c.Query("sortBy"),
+			"tags":             tags,
 		})
 
 		if errn != nil {
@@ -83,13 +97,13 @@ func FindNearbyMerchant(service purchase.Service) fiber.Handler {
 
 // Estimate godoc
 // @Summary      calculate estimate time
-// @Description  calculate estimate time
+// @Description  calculate estimate time and price along the planned route, an optional promoCode is applied and returned in the discount breakdown
 // @Tags         Purchase
 // @Accept       json
 // @Produce      json
 // @Param        request body entities.EstimateRequest true "Estimate request body"
 // @Security     BearerAuth
-// @Success      200  {object}  map[string]interface{}
+// @Success      200  {object}  dtos.EstimateResponse
 // @Failure      400  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /api/v1/users/estimate [post]
@@ -152,25 +166,67 @@ func Estimate(service purchase.Service) fiber.Handler {
 		user := uuid.MustParse(userID.(string))
 		est, errEs := service.Estimate(req, user)
 		if errEs != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(purchaseErrorResponse(errEs))
+		}
+		result, err := dtos.ToEstimateResponse(*est)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(result)
+	}
+}
+
+// GetEstimate godoc
+// @Summary      Get an estimate
+// @Description  Get a calculated estimate of the user with its planned route: the merchants in visiting order ending at the user, the distance and minutes of each leg and the arrival times
+// @Tags         Purchase
+// @Produce      json
+// @Param        estimateId  path  string  true  "Calculated estimate ID"
+// @Security     BearerAuth
+// @Success      200  {object}  dtos.EstimateResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /api/v1/users/estimate/{estimateId} [get]
+func GetEstimate(service purchase.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID := c.Locals("user_id")
+		if userID == nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "unauthorized",
+			})
+		}
+
+		estimateID, err := uuid.Parse(c.Params("estimateId"))
+		if err != nil {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": errEs.Error(),
+				"error": "invalid estimate id",
 			})
 		}
-		// fmt.Println(est)
-		// TODO: panggil service untuk hitung jarak, TSP, estimasi waktu, dll.
-		result := map[string]interface{}{
-			"totalPrice":                   est.TotalPrice,
-			"estimatedDeliveryTimeMinutes": fmt.Sprintf("%.2f", est.EstimatedDelivery),
-			"calculatedEstimateId":         est.ID,
+
+		est, err := service.GetEstimate(estimateID, uuid.MustParse(userID.(string)))
+		if err != nil {
+			return c.Status(estimateErrorStatus(err)).JSON(purchaseErrorResponse(err))
 		}
 
+		result, err := dtos.ToEstimateResponse(*est)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusOK).JSON(result)
 	}
 }
 
 // Order godoc
 // @Summary      order
-// @Description  order based on estimate time id
+// @Description  order based on estimate time id, an estimate can only be ordered once by the user who requested it and before it expires.
+// @Description  The estimate total is honored while current prices stay within the configured tolerance, otherwise 409 returns a new quote to order instead
 // @Tags         Purchase
 // @Accept       json
 // @Produce      json
@@ -178,6 +234,10 @@ func Estimate(service purchase.Service) fiber.Handler {
 // @Security     BearerAuth
 // @Success      200  {object}  map[string]interface{}
 // @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]interface{}
+// @Failure      410  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /api/v1/users/orders [post]
 func Order(service purchase.Service) fiber.Handler {
@@ -197,12 +257,9 @@ func Order(service purchase.Service) fiber.Handler {
 			})
 		}
 
-		// TODO: panggil service untuk hitung jarak, TSP, estimasi waktu, dll.
 		result, err := service.Order(req, uuid.MustParse(userID.(string)))
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+			return c.Status(orderErrorStatus(err)).JSON(purchaseErrorResponse(err))
 		}
 		data := map[string]interface{}{
 			"orderId": result,
@@ -268,3 +325,62 @@ func GetOrder(service purchase.Service) fiber.Handler {
 		return c.Status(fiber.StatusOK).JSON(data_merchants)
 	}
 }
+
+// estimateErrorStatus maps estimate lookup errors to HTTP status codes
+func estimateErrorStatus(err error) int {
+	switch err {
+	case purchase.ErrEstimateNotFound:
+		return fiber.StatusNotFound
+	case purchase.ErrEstimateNotOwned:
+		return fiber.StatusForbidden
+	}
+	return fiber.StatusInternalServerError
+}
+
+// orderErrorStatus maps order errors to HTTP status codes, other errors are rejected orders
+func orderErrorStatus(err error) int {
+	var priceErr *purchase.PriceChangedError
+	if errors.As(err, &priceErr) {
+		return fiber.StatusConflict
+	}
+	switch err {
+	case purchase.ErrInvalidEstimateID:
+		return fiber.StatusBadRequest
+	case purchase.ErrEstimateNotFound:
+		return fiber.StatusNotFound
+	case purchase.ErrEstimateNotOwned:
+		return fiber.StatusForbidden
+	case purchase.ErrEstimateExpired, purchase.ErrEstimateUsed:
+		return fiber.StatusGone
+	}
+	return fiber.StatusBadRequest
+}
+
+// purchaseErrorResponse builds the error body of the purchase endpoints,
+// out of stock errors also list the offending itemIds and price changes carry the new quote
+func purchaseErrorResponse(err error) fiber.Map {
+	var stockErr *purchase.OutOfStockError
+	if errors.As(err, &stockErr) {
+		return fiber.Map{
+			"error":   stockErr.Error(),
+			"itemIds": stockErr.ItemIDs,
+		}
+	}
+	var priceErr *purchase.PriceChangedError
+	if errors.As(err, &priceErr) {
+		quote, errQuote := dtos.ToEstimateResponse(*priceErr.Quote)
+		if errQuote != nil {
+			return fiber.Map{
+				"error": errQuote.Error(),
+			}
+		}
+		return fiber.Map{
+			"error":              priceErr.Error(),
+			"previousTotalPrice": priceErr.PreviousTotal,
+			"quote":              quote,
+		}
+	}
+	return fiber.Map{
+		"error": err.Error(),
+	}
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"belimang/src/api/presenter"
+	"belimang/src/pkg/dtos"
+	"belimang/src/pkg/entities"
+	"belimang/src/pkg/review"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+var validateReview = dtos.NewValidator()
+
+// CreateReview is handler/controller which lets a user review a merchant of one of their orders
+// @Summary      Review a merchant
+// @Description  Rate a merchant from 1 to 5 for an order the user placed, once per merchant per order
+// @Tags         Reviews
+// @Accept       json
+// @Produce      json
+// @Param        merchantId  path      string                  true  "Merchant ID (UUID)"
+// @Param        review      body      entities.RequestReview  true  "Review"
+// @Success      201   {object}  dtos.ReviewResponse
+// @Failure      400   {object}  presenter.ValidationError
+// @Failure      403   {object}  map[string]interface{}
+// @Failure      409   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/merchants/{merchantId}/reviews [post]
+func CreateReview(service review.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, _ := c.Locals("user_id").(string)
+		userID, err := uuid.Parse(uid)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse("invalid token claims"))
+		}
+
+		var requestBody entities.RequestReview
+
+		merchantIdUUID, err := uuid.Parse(c.Params("merchantId"))
+		if err != nil {
+			return c.Status(http.StatusForbidden).
+				JSON(presenter.ErrorResponse(review.ErrReviewNotAllowed.Error()))
+		}
+
+		if err := c.BodyParser(&requestBody); err != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse("invalid request body: " + err.Error()))
+		}
+
+		if errVal := validateReview.Struct(requestBody); errVal != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ValidationErrorResponse(errVal))
+		}
+
+		result, err := service.CreateReview(userID, merchantIdUUID, requestBody)
+		if err != nil {
+			return c.Status(reviewErrorStatus(err)).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.Status(http.StatusCreated).JSON(result)
+	}
+}
+
+// GetMerchantReviews is handler/controller which lists the visible reviews of a merchant
+// @Summary      List merchant reviews
+// @Description  Get the reviews of a merchant, newest first. Hidden reviews are not listed.
+// @Tags         Reviews
+// @Produce      json
+// @Param        merchantId  path   string  true   "Merchant ID (UUID)"
+// @Param        limit       query  int     false  "Limit results (default: 5)"
+// @Param        offset      query  int     false  "Pagination offset (default: 0)"
+// @Success      200   {object}  dtos.ReviewListResponse
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/merchants/{merchantId}/reviews [get]
+func GetMerchantReviews(service review.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		limit, offset := reviewPagination(c)
+
+		merchantIdUUID, err := uuid.Parse(c.Params("merchantId"))
+		if err != nil {
+			// merchantId yang tidak valid tidak punya review
+			merchantIdUUID = uuid.Nil
+		}
+
+		result, err := service.FetchMerchantReviews(merchantIdUUID, map[string]interface{}{
+			"limit":  limit,
+			"offset": offset,
+		})
+		if err != nil {
+			return c.Status(http.StatusInternalServerError).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.Status(http.StatusOK).JSON(result)
+	}
+}
+
+// GetReviews is handler/controller which lists reviews for moderation
+// @Summary      List reviews for moderation
+// @Description  Get the reviews of the merchants managed by the admin, including hidden ones
+// @Tags         Reviews
+// @Produce      json
+// @Param        merchantId  query  string  false  "Merchant ID"
+// @Param        isHidden    query  bool    false  "Filter on moderation state"
+// @Param        limit       query  int     false  "Limit results (default: 5)"
+// @Param        offset      query  int     false  "Pagination offset (default: 0)"
+// @Success      200   {object}  dtos.AdminReviewListResponse
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/reviews [get]
+func GetReviews(service review.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		limit, offset := reviewPagination(c)
+		params := map[string]interface{}{
+			"limit":  limit,
+			"offset": offset,
+		}
+		if v := c.Query("merchantId"); v != "" {
+			id, err := uuid.Parse(v)
+			if err != nil {
+				id = uuid.Nil
+			}
+			params["merchantId"] = id
+		}
+		if v, err := strconv.ParseBool(c.Query("isHidden")); err == nil {
+			params["isHidden"] = v
+		}
+
+		result, err := service.FetchReviews(actor, params)
+		if err != nil {
+			return c.Status(http.StatusInternalServerError).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.Status(http.StatusOK).JSON(result)
+	}
+}
+
+// ModerateReview is handler/controller which hides or restores a review
+// @Summary      Moderate a review
+// @Description  Hide a review from users and the merchant rating, or restore it
+// @Tags         Reviews
+// @Accept       json
+// @Produce      json
+// @Param        reviewId  path      string                          true  "Review ID (UUID)"
+// @Param        review    body      entities.ModerateReviewRequest  true  "Moderation"
+// @Success      200   {object}  dtos.AdminReviewResponse
+// @Failure      400   {object}  presenter.ValidationError
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/admin/reviews/{reviewId} [patch]
+func ModerateReview(service review.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, err := merchantActor(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		var requestBody entities.ModerateReviewRequest
+
+		reviewIdUUID, err := uuid.Parse(c.Params("reviewId"))
+		if err != nil {
+			return c.Status(http.StatusNotFound).
+				JSON(presenter.ErrorResponse(review.ErrReviewNotFound.Error()))
+		}
+
+		if err := c.BodyParser(&requestBody); err != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse("invalid request body: " + err.Error()))
+		}
+
+		if errVal := validateReview.Struct(requestBody); errVal != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ValidationErrorResponse(errVal))
+		}
+
+		result, err := service.ModerateReview(actor, reviewIdUUID, requestBody)
+		if err != nil {
+			return c.Status(reviewErrorStatus(err)).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+		return c.Status(http.StatusOK).JSON(result)
+	}
+}
+
+func reviewPagination(c *fiber.Ctx) (int, int) {
+	limit, _ := strconv.Atoi(c.Query("limit", "5"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+	if limit <= 0 {
+		limit = 5
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// reviewErrorStatus maps review service errors to HTTP status codes
+func reviewErrorStatus(err error) int {
+	switch err {
+	case review.ErrReviewNotFound:
+		return http.StatusNotFound
+	case review.ErrReviewNotAllowed:
+		return http.StatusForbidden
+	case review.ErrAlreadyReviewed:
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
@@ -88,7 +88,7 @@ func IsUser() fiber.Handler {
 	}
 }
 
-// IsAdmin middleware ensures the authenticated user has 'admin' role
+// IsAdmin middleware ensures the authenticated user has 'admin' or 'superadmin' role
 func IsAdmin() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		role := c.Locals("role")
@@ -100,7 +100,7 @@ func IsAdmin() fiber.Handler {
 			})
 		}
 
-		if role != entities.RoleAdmin {
+		if role != entities.RoleAdmin && role != entities.RoleSuperAdmin {
 			return c.Status(http.StatusForbidden).JSON(user.ErrorResponse{
 				Status:  false,
 				Message: "Forbidden",
@@ -1,5 +1,94 @@
 package presenter
 
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
 func ErrorResponse(msg string) string {
 	return msg
 }
+
+// FieldError describes a single field that failed validation
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationError is the response body returned when a request payload fails validation
+type ValidationError struct {
+	Message string       `json:"message"`
+	Errors  []FieldError `json:"errors"`
+}
+
+// ValidationErrorResponse converts validator errors into a structured response body
+func ValidationErrorResponse(err error) ValidationError {
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return ValidationError{
+			Message: "validation failed",
+			Errors:  []FieldError{{Message: err.Error()}},
+		}
+	}
+
+	result := make([]FieldError, 0, len(fieldErrors))
+	for _, fe := range fieldErrors {
+		// buang nama struct di depan namespace, contoh: RequestMerchant.location.lat -> location.lat
+		field := fe.Namespace()
+		if idx := strings.Index(field, "."); idx >= 0 {
+			field = field[idx+1:]
+		}
+		result = append(result, FieldError{
+			Field:   field,
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: validationMessage(field, fe),
+		})
+	}
+
+	return ValidationError{
+		Message: "validation failed",
+		Errors:  result,
+	}
+}
+
+func validationMessage(field string, fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", field)
+	case "min":
+		return fmt.Sprintf("%s must be at least %s%s", field, fe.Param(), sizeUnit(fe))
+	case "max":
+		return fmt.Sprintf("%s must be at most %s%s", field, fe.Param(), sizeUnit(fe))
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", field, fe.Param())
+	case "url":
+		return fmt.Sprintf("%s must be a valid url", field)
+	case "oneof":
+		return fmt.Sprintf("%s must be one of [%s]", field, fe.Param())
+	case "merchant_category":
+		return fmt.Sprintf("%s must be a known merchant category", field)
+	case "product_category":
+		return fmt.Sprintf("%s must be a known product category", field)
+	default:
+		return fmt.Sprintf("%s failed on the '%s' rule", field, fe.Tag())
+	}
+}
+
+// sizeUnit names what min and max count for the field, numbers are compared by value
+func sizeUnit(fe validator.FieldError) string {
+	switch fe.Kind() {
+	case reflect.String:
+		return " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return " items"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,53 @@
+package presenter
+
+import (
+	"belimang/src/pkg/merchant"
+)
+
+// ImportRowReport lists the validation errors of a single import row
+type ImportRowReport struct {
+	Row    int          `json:"row"`
+	Errors []FieldError `json:"errors"`
+}
+
+// ImportReport is the response body of a merchant import
+type ImportReport struct {
+	DryRun    bool              `json:"dryRun"`
+	Merchants int               `json:"merchants"`
+	Items     int               `json:"items"`
+	Errors    []ImportRowReport `json:"errors"`
+}
+
+// ImportReportResponse builds the per-row error report of an import run
+func ImportReportResponse(result *merchant.ImportResult) ImportReport {
+	report := ImportReport{
+		DryRun:    result.DryRun,
+		Merchants: result.Merchants,
+		Items:     result.Items,
+		Errors:    []ImportRowReport{},
+	}
+
+	byRow := make(map[int]int)
+	for _, rowErr := range result.Errors {
+		fieldErrors := ValidationErrorResponse(rowErr.Err).Errors
+		if rowErr.Path != "" {
+			for i := range fieldErrors {
+				if fieldErrors[i].Field == "" {
+					fieldErrors[i].Field = rowErr.Path
+				} else {
+					fieldErrors[i].Field = rowErr.Path + "." + fieldErrors[i].Field
+				}
+			}
+		}
+
+		idx, ok := byRow[rowErr.Row]
+		if !ok {
+			report.Errors = append(report.Errors, ImportRowReport{Row: rowErr.Row})
+			idx = len(report.Errors) - 1
+			byRow[rowErr.Row] = idx
+		}
+		report.Errors[idx].Errors = append(report.Errors[idx].Errors, fieldErrors...)
+	}
+
+	return report
+}
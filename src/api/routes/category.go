@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"belimang/src/api/handlers"
+	"belimang/src/api/middleware"
+	"belimang/src/pkg/category"
+	"belimang/src/pkg/user"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CategoryRouter sets up the category routes
+func CategoryRouter(app fiber.Router, userService user.Service, categoryService category.Service) {
+
+	// daftar kategori terbuka untuk semua klien
+	app.Get("/categories", handlers.GetCategories(categoryService))
+
+	adminGroup := app.Group("admin/categories", middleware.JWTAuth(userService), middleware.IsAdmin())
+	adminGroup.Post("/", handlers.CreateCategory(categoryService))
+	adminGroup.Patch("/:categoryId", handlers.UpdateCategory(categoryService))
+	adminGroup.Delete("/:categoryId", handlers.DeleteCategory(categoryService))
+}
@@ -2,20 +2,40 @@ package routes
 
 import (
 	"belimang/src/api/handlers"
+	"belimang/src/api/middleware"
 	"belimang/src/pkg/merchant"
+	"belimang/src/pkg/user"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 // ActivityRouter sets up the activity routes
-func MerchantRouter(app fiber.Router, merchantService merchant.Service) {
+func MerchantRouter(app fiber.Router, userService user.Service, merchantService merchant.Service) {
 
-	// Create activity group with JWT middleware
-	merchantGroup := app.Group("admin/merchants")
+	// Create merchant group with JWT middleware, only admins may manage merchants
+	merchantGroup := app.Group("admin/merchants", middleware.JWTAuth(userService), middleware.IsAdmin())
 
 	// Activity routes
 	merchantGroup.Post("/", handlers.CreateMerchant(merchantService))
+	merchantGroup.Get("/", handlers.GetMerchants(merchantService))
+	merchantGroup.Post("/import", handlers.ImportMerchants(merchantService))
+	merchantGroup.Get("/export", handlers.ExportMerchants(merchantService))
+	merchantGroup.Patch("/:merchantId", handlers.UpdateMerchant(merchantService))
+	merchantGroup.Delete("/:merchantId", handlers.DeleteMerchant(merchantService))
+	merchantGroup.Get("/:merchantId/hours", handlers.GetMerchantOpeningHours(merchantService))
+	merchantGroup.Put("/:merchantId/hours", handlers.UpdateMerchantOpeningHours(merchantService))
+	merchantGroup.Post("/:merchantId/closures", handlers.CreateMerchantClosure(merchantService))
+	merchantGroup.Delete("/:merchantId/closures/:closureId", handlers.DeleteMerchantClosure(merchantService))
 	merchantGroup.Post("/:merchantId/items", handlers.CreateMerchantItems(merchantService))
+	merchantGroup.Get("/:merchantId/items", handlers.GetMerchantItems(merchantService))
+	merchantGroup.Patch("/:merchantId/items/:itemId", handlers.UpdateMerchantItems(merchantService))
+	merchantGroup.Delete("/:merchantId/items/:itemId", handlers.DeleteMerchantItems(merchantService))
+	merchantGroup.Get("/:merchantId/items/:itemId/modifiers", handlers.GetItemModifiers(merchantService))
+	merchantGroup.Post("/:merchantId/items/:itemId/modifiers", handlers.CreateItemModifier(merchantService))
+	merchantGroup.Delete("/:merchantId/items/:itemId/modifiers/:modifierGroupId", handlers.DeleteItemModifier(merchantService))
+	merchantGroup.Get("/:merchantId/promotions", handlers.GetMerchantPromotions(merchantService))
+	merchantGroup.Post("/:merchantId/promotions", handlers.CreateMerchantPromotion(merchantService))
+	merchantGroup.Delete("/:merchantId/promotions/:promotionId", handlers.DeleteMerchantPromotion(merchantService))
 	// merchantGroup.Get("/nearby/:lat/:lon", handlers.FindNearbyMerchant(purchaseService))
 
 }
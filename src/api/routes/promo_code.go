@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"belimang/src/api/handlers"
+	"belimang/src/api/middleware"
+	"belimang/src/pkg/promocode"
+	"belimang/src/pkg/user"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PromoCodeRouter sets up the promo code routes
+func PromoCodeRouter(app fiber.Router, userService user.Service, promoCodeService promocode.Service) {
+
+	adminGroup := app.Group("admin/promo-codes", middleware.JWTAuth(userService), middleware.IsAdmin())
+	adminGroup.Post("/", handlers.CreatePromoCode(promoCodeService))
+	adminGroup.Get("/", handlers.GetPromoCodes(promoCodeService))
+	adminGroup.Patch("/:promoCodeId/deactivate", handlers.DeactivatePromoCode(promoCodeService))
+}
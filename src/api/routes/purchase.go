@@ -17,6 +17,7 @@ func PurchaseRouter(app fiber.Router, userService user.Service, service purchase
 
 	purchaseGroup.Get("/nearby/:lat/:lon", middleware.JWTAuth(userService), handlers.FindNearbyMerchant(service))
 	app.Post("users/estimate", middleware.JWTAuth(userService), handlers.Estimate(service))
+	app.Get("/users/estimate/:estimateId", middleware.JWTAuth(userService), handlers.GetEstimate(service))
 	app.Post("/users/orders", middleware.JWTAuth(userService), handlers.Order(service))
 	app.Get("/users/orders", middleware.JWTAuth(userService), handlers.GetOrder(service))
 }
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"belimang/src/api/handlers"
+	"belimang/src/api/middleware"
+	"belimang/src/pkg/review"
+	"belimang/src/pkg/user"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ReviewRouter sets up the review routes
+func ReviewRouter(app fiber.Router, userService user.Service, reviewService review.Service) {
+
+	app.Post("/merchants/:merchantId/reviews", middleware.JWTAuth(userService), handlers.CreateReview(reviewService))
+	app.Get("/merchants/:merchantId/reviews", middleware.JWTAuth(userService), handlers.GetMerchantReviews(reviewService))
+
+	// moderasi hanya untuk admin
+	adminGroup := app.Group("admin/reviews", middleware.JWTAuth(userService), middleware.IsAdmin())
+	adminGroup.Get("/", handlers.GetReviews(reviewService))
+	adminGroup.Patch("/:reviewId", handlers.ModerateReview(reviewService))
+}
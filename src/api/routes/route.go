@@ -1,9 +1,12 @@
 package routes
 
 import (
+	"belimang/src/pkg/category"
 	"belimang/src/pkg/image"
 	"belimang/src/pkg/merchant"
+	"belimang/src/pkg/promocode"
 	"belimang/src/pkg/purchase"
+	"belimang/src/pkg/review"
 	"belimang/src/pkg/user"
 
 	"github.com/gofiber/fiber/v2"
@@ -21,8 +24,11 @@ func SetupRoutes(app *fiber.App, v *viper.Viper, db *gorm.DB, services Services)
 	api := app.Group("/api/v1")
 
 	// BookRouter(api, services.BookService)
-	MerchantRouter(api, services.MerchantService)
+	MerchantRouter(api, services.UserService, services.MerchantService)
 	PurchaseRouter(api, services.UserService, services.PurchaseService)
+	ReviewRouter(api, services.UserService, services.ReviewService)
+	CategoryRouter(api, services.UserService, services.CategoryService)
+	PromoCodeRouter(api, services.UserService, services.PromoCodeService)
 
 	// --- Health check route for Kubernetes probes ---
 	app.Get("/healthz", func(c *fiber.Ctx) error {
@@ -44,8 +50,11 @@ type Services struct {
 	UserService  user.Service
 	ImageService image.Service
 
-	MerchantService merchant.Service
-	PurchaseService purchase.Service
+	MerchantService  merchant.Service
+	PurchaseService  purchase.Service
+	ReviewService    review.Service
+	CategoryService  category.Service
+	PromoCodeService promocode.Service
 	// ActivityService   activity.Service
 	// UploadFileService userfile.Service
 }
@@ -4,6 +4,9 @@ import (
 	"belimang/src/api/routes"
 	"belimang/src/config"
 	"log"
+
+	// embed the timezone database, merchant opening hours rely on it
+	_ "time/tzdata"
 )
 
 // @title           belimang API
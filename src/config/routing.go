@@ -0,0 +1,25 @@
+package config
+
+import (
+	"belimang/src/pkg/purchase"
+	"log"
+	"os"
+	"time"
+)
+
+// InitDistanceProvider loads the road network from the OSM extract (.osm.pbf or .osm) in ROAD_GRAPH_FILE,
+// falling back to straight-line distances when it is not set
+func InitDistanceProvider() purchase.DistanceProvider {
+	path := os.Getenv("ROAD_GRAPH_FILE")
+	if path == "" {
+		return purchase.StraightLine{}
+	}
+
+	started := time.Now()
+	network, err := purchase.LoadRoadNetwork(path)
+	if err != nil {
+		log.Fatalf("Failed to load road network: %v", err)
+	}
+	log.Printf("Road network loaded: %d nodes in %s", network.Nodes(), time.Since(started).Round(time.Millisecond))
+	return network
+}
@@ -2,11 +2,17 @@ package config
 
 import (
 	"belimang/src/api/routes"
+	"belimang/src/pkg/category"
 	"belimang/src/pkg/image"
 	"belimang/src/pkg/merchant"
+	"belimang/src/pkg/promocode"
 	"belimang/src/pkg/purchase"
+	"belimang/src/pkg/review"
 	"belimang/src/pkg/user"
+	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/minio/minio-go/v7"
 	"gorm.io/gorm"
@@ -27,14 +33,26 @@ func InitServices(db *gorm.DB, minioClient *minio.Client) routes.Services {
 	userService := user.NewService(userRepo, jwtSecret)
 	imageService := image.NewService(minioClient)
 
+	//category
+	categoryRepo := category.NewRepo(db)
+	categoryService := category.NewService(categoryRepo)
+
 	//merchant
 
 	merchantRepo := merchant.NewRepo(db)
-	merchantService := merchant.NewService(merchantRepo)
+	merchantService := merchant.NewService(merchantRepo, categoryService)
 
 	//purchase
 	purchaseRepo := purchase.NewRepo(db)
-	purchaseService := purchase.NewService(purchaseRepo)
+	purchaseService := purchase.NewService(purchaseRepo, InitDistanceProvider(), InitTravelTimeModel(), purchaseConfig())
+
+	//review
+	reviewRepo := review.NewRepo(db)
+	reviewService := review.NewService(reviewRepo)
+
+	//promo code
+	promoCodeRepo := promocode.NewRepo(db)
+	promoCodeService := promocode.NewService(promoCodeRepo)
 
 	//
 
@@ -42,7 +60,31 @@ func InitServices(db *gorm.DB, minioClient *minio.Client) routes.Services {
 		UserService:  userService,
 		ImageService: imageService,
 		// BookService:     bookService,
-		MerchantService: merchantService,
-		PurchaseService: purchaseService,
+		MerchantService:  merchantService,
+		PurchaseService:  purchaseService,
+		ReviewService:    reviewService,
+		CategoryService:  categoryService,
+		PromoCodeService: promoCodeService,
+	}
+}
+
+// purchaseConfig reads the purchase settings from the environment, ESTIMATE_TTL is a duration such as "15m"
+// and PRICE_TOLERANCE_PERCENT the price change still honored at the estimate price
+func purchaseConfig() purchase.Config {
+	var cfg purchase.Config
+	if v := os.Getenv("ESTIMATE_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl <= 0 {
+			log.Fatalf("Invalid ESTIMATE_TTL %q: must be a positive duration such as 15m", v)
+		}
+		cfg.EstimateTTL = ttl
+	}
+	if v := os.Getenv("PRICE_TOLERANCE_PERCENT"); v != "" {
+		tolerance, err := strconv.ParseFloat(v, 64)
+		if err != nil || tolerance < 0 {
+			log.Fatalf("Invalid PRICE_TOLERANCE_PERCENT %q: must be a number of at least 0", v)
+		}
+		cfg.PriceTolerancePercent = tolerance
 	}
+	return cfg
 }
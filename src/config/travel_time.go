@@ -0,0 +1,25 @@
+package config
+
+import (
+	"belimang/src/pkg/purchase"
+	"log"
+	"os"
+)
+
+// InitTravelTimeModel loads the travel time model from the file in TRAVEL_TIME_CONFIG (YAML or JSON),
+// falling back to the built-in vehicle speeds when it is not set
+func InitTravelTimeModel() purchase.TravelTimeModel {
+	cfg := purchase.DefaultTravelTimeConfig()
+	if path := os.Getenv("TRAVEL_TIME_CONFIG"); path != "" {
+		var err error
+		if cfg, err = purchase.LoadTravelTimeConfig(path); err != nil {
+			log.Fatalf("Failed to read travel time config: %v", err)
+		}
+	}
+
+	model, err := purchase.NewTravelTimeModel(cfg)
+	if err != nil {
+		log.Fatalf("Invalid travel time config: %v", err)
+	}
+	return model
+}
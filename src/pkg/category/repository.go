@@ -0,0 +1,110 @@
+package category
+
+import (
+	"belimang/src/pkg/entities"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Repository interface allows us to access the CRUD Operations here.
+type Repository interface {
+	FindCategories(kind string) ([]entities.Category, error)
+	FindCategoryById(categoryId uuid.UUID) (*entities.Category, error)
+	FindCategoryByName(kind, name string) (*entities.Category, error)
+	CreateCategory(category *entities.Category) (*entities.Category, error)
+	RenameCategory(category *entities.Category, oldName string) (*entities.Category, error)
+	CountUsage(category *entities.Category) (int64, error)
+	DeleteCategory(categoryId uuid.UUID) error
+}
+type repository struct {
+	DB *gorm.DB
+}
+
+// NewRepo is the single instance repo that is being created.
+func NewRepo(db *gorm.DB) Repository {
+	return &repository{
+		DB: db,
+	}
+}
+
+// FindCategories lists the categories of a kind, or every category when kind is empty
+func (r *repository) FindCategories(kind string) ([]entities.Category, error) {
+	var categories []entities.Category
+	query := r.DB.Model(&entities.Category{})
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if err := query.Order("kind, name").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// FindCategoryById retrieves a category by its ID, returning nil when it does not exist
+func (r *repository) FindCategoryById(categoryId uuid.UUID) (*entities.Category, error) {
+	var category entities.Category
+	if err := r.DB.Where("id = ?", categoryId).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+// FindCategoryByName retrieves a category by its kind and name, returning nil when it does not exist
+func (r *repository) FindCategoryByName(kind, name string) (*entities.Category, error) {
+	var category entities.Category
+	if err := r.DB.Where("kind = ? AND name = ?", kind, name).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *repository) CreateCategory(category *entities.Category) (*entities.Category, error) {
+	if err := r.DB.Create(category).Error; err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// RenameCategory saves a new category name and moves the merchants or items using the old name along
+func (r *repository) RenameCategory(category *entities.Category, oldName string) (*entities.Category, error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(category).Error; err != nil {
+			return err
+		}
+		table, column := usage(category.Kind)
+		// termasuk data yang sudah dihapus supaya tetap konsisten jika dipulihkan
+		return tx.Table(table).Where(column+" = ?", oldName).Update(column, category.Name).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// CountUsage counts the merchants or items still using the category
+func (r *repository) CountUsage(category *entities.Category) (int64, error) {
+	var count int64
+	table, column := usage(category.Kind)
+	err := r.DB.Table(table).Where(column+" = ? AND deleted_at IS NULL", category.Name).Count(&count).Error
+	return count, err
+}
+
+func (r *repository) DeleteCategory(categoryId uuid.UUID) error {
+	return r.DB.Delete(&entities.Category{}, "id = ?", categoryId).Error
+}
+
+// usage returns the table and column referencing categories of the given kind
+func usage(kind string) (string, string) {
+	if kind == entities.CategoryKindMerchant {
+		return "merchants", "merchant_category"
+	}
+	return "items", "product_category"
+}
@@ -0,0 +1,183 @@
+package category
+
+import (
+	"belimang/src/pkg/dtos"
+	"belimang/src/pkg/entities"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrCategoryExists   = errors.New("category already exists")
+	ErrCategoryInUse    = errors.New("category is still used")
+)
+
+// cacheTTL bounds how long other instances may validate against a stale category list
+const cacheTTL = time.Minute
+
+// Service is an interface from which our api module can access our repository of all our models
+type Service interface {
+	FetchCategories() (*dtos.CategoryListResponse, error)
+	CreateCategory(req entities.RequestCategory) (*dtos.CategoryResponse, error)
+	UpdateCategory(categoryId uuid.UUID, req entities.UpdateCategoryRequest) (*dtos.CategoryResponse, error)
+	RemoveCategory(categoryId uuid.UUID) error
+	Exists(kind, name string) (bool, error)
+}
+
+type service struct {
+	repository Repository
+
+	mu       sync.RWMutex
+	names    map[string]map[string]bool
+	loadedAt time.Time
+}
+
+// NewService is used to create a single instance of the service
+func NewService(r Repository) Service {
+	return &service{
+		repository: r,
+	}
+}
+
+// FetchCategories is a service layer that helps list every category grouped by kind
+func (s *service) FetchCategories() (*dtos.CategoryListResponse, error) {
+	categories, err := s.repository.FindCategories("")
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dtos.CategoryListResponse{
+		MerchantCategories: []dtos.CategoryResponse{},
+		ProductCategories:  []dtos.CategoryResponse{},
+	}
+	for _, c := range categories {
+		if c.Kind == entities.CategoryKindMerchant {
+			res.MerchantCategories = append(res.MerchantCategories, dtos.ToCategoryResponse(c))
+		} else {
+			res.ProductCategories = append(res.ProductCategories, dtos.ToCategoryResponse(c))
+		}
+	}
+	return res, nil
+}
+
+// CreateCategory is a service layer that helps add a category
+func (s *service) CreateCategory(req entities.RequestCategory) (*dtos.CategoryResponse, error) {
+	existing, err := s.repository.FindCategoryByName(req.Kind, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrCategoryExists
+	}
+
+	category, err := s.repository.CreateCategory(&entities.Category{
+		Kind: req.Kind,
+		Name: req.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	res := dtos.ToCategoryResponse(*category)
+	return &res, nil
+}
+
+// UpdateCategory is a service layer that helps rename a category together with everything using it
+func (s *service) UpdateCategory(categoryId uuid.UUID, req entities.UpdateCategoryRequest) (*dtos.CategoryResponse, error) {
+	category, err := s.repository.FindCategoryById(categoryId)
+	if err != nil {
+		return nil, err
+	}
+	if category == nil {
+		return nil, ErrCategoryNotFound
+	}
+	if category.Name == req.Name {
+		res := dtos.ToCategoryResponse(*category)
+		return &res, nil
+	}
+
+	existing, err := s.repository.FindCategoryByName(category.Kind, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrCategoryExists
+	}
+
+	oldName := category.Name
+	category.Name = req.Name
+	category, err = s.repository.RenameCategory(category, oldName)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	res := dtos.ToCategoryResponse(*category)
+	return &res, nil
+}
+
+// RemoveCategory is a service layer that helps delete a category no merchant or item uses anymore
+func (s *service) RemoveCategory(categoryId uuid.UUID) error {
+	category, err := s.repository.FindCategoryById(categoryId)
+	if err != nil {
+		return err
+	}
+	if category == nil {
+		return ErrCategoryNotFound
+	}
+
+	used, err := s.repository.CountUsage(category)
+	if err != nil {
+		return err
+	}
+	if used > 0 {
+		return ErrCategoryInUse
+	}
+
+	if err := s.repository.DeleteCategory(categoryId); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// Exists reports whether a category of the given kind exists, reading from a cache refreshed every cacheTTL
+func (s *service) Exists(kind, name string) (bool, error) {
+	s.mu.RLock()
+	if s.names != nil && time.Since(s.loadedAt) < cacheTTL {
+		ok := s.names[kind][name]
+		s.mu.RUnlock()
+		return ok, nil
+	}
+	s.mu.RUnlock()
+
+	categories, err := s.repository.FindCategories("")
+	if err != nil {
+		return false, err
+	}
+	names := map[string]map[string]bool{}
+	for _, c := range categories {
+		if names[c.Kind] == nil {
+			names[c.Kind] = map[string]bool{}
+		}
+		names[c.Kind][c.Name] = true
+	}
+
+	s.mu.Lock()
+	s.names = names
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+
+	return names[kind][name], nil
+}
+
+func (s *service) invalidate() {
+	s.mu.Lock()
+	s.names = nil
+	s.mu.Unlock()
+}
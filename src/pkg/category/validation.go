@@ -0,0 +1,42 @@
+package category
+
+import (
+	"belimang/src/pkg/entities"
+	"context"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type lookupErrorKey struct{}
+
+// RegisterValidations adds the merchant_category and product_category tags to v,
+// checking values against the categories stored in the database
+func RegisterValidations(v *validator.Validate, s Service) {
+	register := func(tag, kind string) {
+		v.RegisterValidationCtx(tag, func(ctx context.Context, fl validator.FieldLevel) bool {
+			ok, err := s.Exists(kind, fl.Field().String())
+			if err != nil {
+				// validator hanya menerima bool, error disimpan untuk Validate
+				if lookupErr, _ := ctx.Value(lookupErrorKey{}).(*error); lookupErr != nil && *lookupErr == nil {
+					*lookupErr = err
+				}
+				return false
+			}
+			return ok
+		})
+	}
+	register("merchant_category", entities.CategoryKindMerchant)
+	register("product_category", entities.CategoryKindProduct)
+}
+
+// Validate checks s with v. When the categories could not be looked up it returns that error
+// instead of validator.ValidationErrors, so it is not reported as an invalid request.
+func Validate(v *validator.Validate, s interface{}) error {
+	var lookupErr error
+	err := v.StructCtx(context.WithValue(context.Background(), lookupErrorKey{}, &lookupErr), s)
+	if lookupErr != nil {
+		return fmt.Errorf("category lookup: %w", lookupErr)
+	}
+	return err
+}
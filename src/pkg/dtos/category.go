@@ -0,0 +1,27 @@
+package dtos
+
+import (
+	"belimang/src/pkg/entities"
+
+	"github.com/google/uuid"
+)
+
+type CategoryResponse struct {
+	CategoryID uuid.UUID `json:"categoryId"`
+	Kind       string    `json:"kind"`
+	Name       string    `json:"name"`
+}
+
+type CategoryListResponse struct {
+	MerchantCategories []CategoryResponse `json:"merchantCategories"`
+	ProductCategories  []CategoryResponse `json:"productCategories"`
+}
+
+// ToCategoryResponse converts a category entity into its API representation
+func ToCategoryResponse(c entities.Category) CategoryResponse {
+	return CategoryResponse{
+		CategoryID: c.ID,
+		Kind:       c.Kind,
+		Name:       c.Name,
+	}
+}
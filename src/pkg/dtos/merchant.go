@@ -0,0 +1,191 @@
+package dtos
+
+import (
+	"belimang/src/pkg/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MerchantListResponse struct {
+	Data []MerchantResponse `json:"data"`
+	Meta MetaResponse       `json:"meta"`
+}
+
+type ItemListResponse struct {
+	Data []ItemResponse `json:"data"`
+	Meta MetaResponse   `json:"meta"`
+}
+
+// ToMerchantResponse converts a merchant entity into its API representation
+func ToMerchantResponse(m entities.Merchant) MerchantResponse {
+	return MerchantResponse{
+		MerchantID:       m.ID,
+		Name:             m.Name,
+		MerchantCategory: string(m.MerchantCategory),
+		ImageURL:         m.ImageUrl,
+		Location: LocationResponse{
+			Lat:  m.Lat,
+			Long: m.Long,
+		},
+		MaxDeliveryKm: m.MaxDeliveryKm,
+		MinOrderValue: m.MinOrderValue,
+		CreatedAt:     FormatNanosToISO8601(m.CreatedAt),
+	}
+}
+
+// ToItemResponse converts an item entity into its API representation
+func ToItemResponse(i entities.Items) ItemResponse {
+	return ItemResponse{
+		ItemID:          i.ID,
+		Name:            i.Name,
+		ProductCategory: string(i.ProductCategory),
+		Price:           i.Price,
+		EffectivePrice:  i.Price,
+		ImageURL:        i.ImageUrl,
+		Stock:           i.Stock,
+		IsAvailable:     i.IsAvailable,
+		Tags:            i.TagNames(),
+		CreatedAt:       FormatNanosToISO8601(i.CreatedAt),
+	}
+}
+
+// ApplyPromotions sets the effective price of an item from the best promotion running at t
+func (r *ItemResponse) ApplyPromotions(item entities.Items, timezone string, promotions []entities.Promotion, t time.Time) {
+	price, promotion := entities.EffectivePrice(item, timezone, promotions, t)
+	r.EffectivePrice = price
+	if promotion != nil {
+		r.PromotionID = &promotion.ID
+	}
+}
+
+type ModifierGroupResponse struct {
+	ModifierGroupID uuid.UUID                `json:"modifierGroupId"`
+	Name            string                   `json:"name"`
+	IsRequired      bool                     `json:"isRequired"`
+	MinSelect       int                      `json:"minSelect"`
+	MaxSelect       int                      `json:"maxSelect"`
+	Options         []ModifierOptionResponse `json:"options"`
+}
+
+type ModifierOptionResponse struct {
+	OptionID   uuid.UUID      `json:"optionId"`
+	Name       string         `json:"name"`
+	PriceDelta entities.Money `json:"priceDelta"`
+}
+
+// ToModifierGroupResponses converts the modifier groups of an item into their API representation
+func ToModifierGroupResponses(groups []entities.ModifierGroup) []ModifierGroupResponse {
+	res := make([]ModifierGroupResponse, len(groups))
+	for i, g := range groups {
+		res[i] = ModifierGroupResponse{
+			ModifierGroupID: g.ID,
+			Name:            g.Name,
+			IsRequired:      g.IsRequired,
+			MinSelect:       g.MinSelect,
+			MaxSelect:       g.MaxSelect,
+			Options:         make([]ModifierOptionResponse, len(g.Options)),
+		}
+		for j, o := range g.Options {
+			res[i].Options[j] = ModifierOptionResponse{
+				OptionID:   o.ID,
+				Name:       o.Name,
+				PriceDelta: o.PriceDelta,
+			}
+		}
+	}
+	return res
+}
+
+// FormatNanosToISO8601 formats a unix timestamp in nanoseconds (as stored in created_at)
+func FormatNanosToISO8601(nanos int64) string {
+	sec := nanos / 1e9
+	nsec := nanos % 1e9
+	t := time.Unix(sec, nsec)
+	// Format manual agar tetap ada 9 digit nanodetik
+	return t.Format("2006-01-02T15:04:05.000000000Z07:00")
+}
+
+type OpeningHoursResponse struct {
+	Timezone string              `json:"timezone"`
+	Hours    []OpeningHoursEntry `json:"hours"`
+	Closures []ClosureResponse   `json:"closures"`
+}
+
+type OpeningHoursEntry struct {
+	DayOfWeek int    `json:"dayOfWeek"`
+	Open      string `json:"open"`
+	Close     string `json:"close"`
+}
+
+type ClosureResponse struct {
+	ClosureID uuid.UUID `json:"closureId"`
+	StartDate string    `json:"startDate"`
+	EndDate   string    `json:"endDate"`
+	Reason    string    `json:"reason"`
+}
+
+// ToOpeningHoursResponse converts a merchant schedule into its API representation
+func ToOpeningHoursResponse(timezone string, hours []entities.OpeningHours, closures []entities.MerchantClosure) OpeningHoursResponse {
+	res := OpeningHoursResponse{
+		Timezone: timezone,
+		Hours:    make([]OpeningHoursEntry, len(hours)),
+		Closures: make([]ClosureResponse, len(closures)),
+	}
+	for i, h := range hours {
+		res.Hours[i] = OpeningHoursEntry{
+			DayOfWeek: h.DayOfWeek,
+			Open:      entities.FormatClock(h.OpenMinute),
+			Close:     entities.FormatClock(h.CloseMinute),
+		}
+	}
+	for i, c := range closures {
+		res.Closures[i] = ClosureResponse{
+			ClosureID: c.ID,
+			StartDate: c.StartDate.UTC().Format(time.DateOnly),
+			EndDate:   c.EndDate.UTC().Format(time.DateOnly),
+			Reason:    c.Reason,
+		}
+	}
+	return res
+}
+
+type PromotionResponse struct {
+	PromotionID     uuid.UUID      `json:"promotionId"`
+	Name            string         `json:"name"`
+	Scope           string         `json:"scope"`
+	ProductCategory string         `json:"productCategory,omitempty"`
+	ItemIDs         []uuid.UUID    `json:"itemIds,omitempty"`
+	DiscountType    string         `json:"discountType"`
+	DiscountPercent float64        `json:"discountPercent,omitempty"`
+	DiscountAmount  entities.Money `json:"discountAmount,omitempty"`
+	StartsAt        string         `json:"startsAt"`
+	EndsAt          string         `json:"endsAt"`
+	DailyStart      string         `json:"dailyStart,omitempty"`
+	DailyEnd        string         `json:"dailyEnd,omitempty"`
+	CreatedAt       string         `json:"createdAt"`
+}
+
+// ToPromotionResponse converts a promotion into its API representation
+func ToPromotionResponse(p entities.Promotion) PromotionResponse {
+	res := PromotionResponse{
+		PromotionID:     p.ID,
+		Name:            p.Name,
+		Scope:           p.Scope,
+		ProductCategory: string(p.ProductCategory),
+		DiscountType:    p.DiscountType,
+		DiscountPercent: p.DiscountPercent,
+		DiscountAmount:  p.DiscountAmount,
+		StartsAt:        p.StartsAt.Format(time.RFC3339),
+		EndsAt:          p.EndsAt.Format(time.RFC3339),
+		CreatedAt:       FormatNanosToISO8601(p.CreatedAt),
+	}
+	for _, i := range p.Items {
+		res.ItemIDs = append(res.ItemIDs, i.ItemID)
+	}
+	if p.DailyStartMinute != nil && p.DailyEndMinute != nil {
+		res.DailyStart = entities.FormatClock(*p.DailyStartMinute)
+		res.DailyEnd = entities.FormatClock(*p.DailyEndMinute)
+	}
+	return res
+}
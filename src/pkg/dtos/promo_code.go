@@ -0,0 +1,77 @@
+package dtos
+
+import (
+	"belimang/src/pkg/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PromoCodeResponse struct {
+	PromoCodeID     uuid.UUID       `json:"promoCodeId"`
+	Code            string          `json:"code"`
+	DiscountType    string          `json:"discountType"`
+	DiscountPercent float64         `json:"discountPercent,omitempty"`
+	DiscountAmount  entities.Money  `json:"discountAmount,omitempty"`
+	MinSpend        entities.Money  `json:"minSpend"`
+	MaxDiscount     *entities.Money `json:"maxDiscount"`
+	UsageLimit      *int            `json:"usageLimit"`
+	PerUserLimit    *int            `json:"perUserLimit"`
+	UsedCount       int64           `json:"usedCount"`
+	StartsAt        string          `json:"startsAt"`
+	EndsAt          string          `json:"endsAt"`
+	IsActive        bool            `json:"isActive"`
+	MerchantIDs     []uuid.UUID     `json:"merchantIds"`
+	CreatedAt       string          `json:"createdAt"`
+}
+
+type PromoCodeListResponse struct {
+	Data []PromoCodeResponse `json:"data"`
+	Meta MetaResponse        `json:"meta"`
+}
+
+// ToPromoCodeResponse converts a promo code with its usage count into its API representation
+func ToPromoCodeResponse(p entities.PromoCode, used int64) PromoCodeResponse {
+	res := PromoCodeResponse{
+		PromoCodeID:     p.ID,
+		Code:            p.Code,
+		DiscountType:    p.DiscountType,
+		DiscountPercent: p.DiscountPercent,
+		DiscountAmount:  p.DiscountAmount,
+		MinSpend:        p.MinSpend,
+		MaxDiscount:     p.MaxDiscount,
+		UsageLimit:      p.UsageLimit,
+		PerUserLimit:    p.PerUserLimit,
+		UsedCount:       used,
+		StartsAt:        p.StartsAt.Format(time.RFC3339),
+		EndsAt:          p.EndsAt.Format(time.RFC3339),
+		IsActive:        p.IsActive,
+		MerchantIDs:     make([]uuid.UUID, len(p.Merchants)),
+		CreatedAt:       FormatNanosToISO8601(p.CreatedAt),
+	}
+	for i, m := range p.Merchants {
+		res.MerchantIDs[i] = m.MerchantID
+	}
+	return res
+}
+
+// DiscountBreakdown explains how the total price of an estimate was reached
+type DiscountBreakdown struct {
+	Subtotal          entities.Money `json:"subtotal"`          // sebelum diskon
+	PromotionDiscount entities.Money `json:"promotionDiscount"` // promosi merchant
+	PromoCode         string         `json:"promoCode,omitempty"`
+	PromoCodeDiscount entities.Money `json:"promoCodeDiscount"`
+	TotalDiscount     entities.Money `json:"totalDiscount"`
+}
+
+// ToDiscountBreakdown builds the discount breakdown of an estimate
+func ToDiscountBreakdown(est entities.DeliveryEstimate) DiscountBreakdown {
+	totalDiscount := est.PromotionDiscount + est.PromoCodeDiscount
+	return DiscountBreakdown{
+		Subtotal:          est.TotalPrice + totalDiscount,
+		PromotionDiscount: est.PromotionDiscount,
+		PromoCode:         est.PromoCode,
+		PromoCodeDiscount: est.PromoCodeDiscount,
+		TotalDiscount:     totalDiscount,
+	}
+}
@@ -1,6 +1,11 @@
 package dtos
 
 import (
+	"belimang/src/pkg/entities"
+	"encoding/json"
+	"fmt"
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -15,16 +20,26 @@ type MerchantResponse struct {
 	MerchantCategory string           `json:"merchantCategory"`
 	ImageURL         string           `json:"imageUrl"`
 	Location         LocationResponse `json:"location"`
+	MaxDeliveryKm    float64          `json:"maxDeliveryKm"`
+	MinOrderValue    entities.Money   `json:"minOrderValue"`
+	IsOpen           *bool            `json:"isOpen,omitempty"`
+	Rating           *RatingSummary   `json:"rating,omitempty"`
 	CreatedAt        string           `json:"createdAt"`
 }
 
 type ItemResponse struct {
-	ItemID          uuid.UUID `json:"itemId"`
-	Name            string    `json:"name"`
-	ProductCategory string    `json:"productCategory"`
-	Price           float64   `json:"price"`
-	ImageURL        string    `json:"imageUrl"`
-	CreatedAt       string    `json:"createdAt"`
+	ItemID          uuid.UUID               `json:"itemId"`
+	Name            string                  `json:"name"`
+	ProductCategory string                  `json:"productCategory"`
+	Price           entities.Money          `json:"price"`          // harga asli
+	EffectivePrice  entities.Money          `json:"effectivePrice"` // harga setelah promosi
+	PromotionID     *uuid.UUID              `json:"promotionId,omitempty"`
+	ImageURL        string                  `json:"imageUrl"`
+	Stock           *int                    `json:"stock"`
+	IsAvailable     bool                    `json:"isAvailable"`
+	Tags            []string                `json:"tags"`
+	Modifiers       []ModifierGroupResponse `json:"modifiers,omitempty"`
+	CreatedAt       string                  `json:"createdAt"`
 }
 
 type MerchantWithItems struct {
@@ -55,8 +70,41 @@ type OrderDetail struct {
 	ItemID            uuid.UUID
 	ItemName          string
 	ProductCategory   string
-	Price             float64
+	Price             entities.Money
 	Quantity          int
+	Options           string
 	ItemImageURL      string
 	ItemCreatedAt     int64
 }
+
+// EstimateResponse is an estimate with its price breakdown and planned route
+type EstimateResponse struct {
+	CalculatedEstimateID         uuid.UUID            `json:"calculatedEstimateId"`
+	TotalPrice                   entities.Money       `json:"totalPrice"`
+	EstimatedDeliveryTimeMinutes string               `json:"estimatedDeliveryTimeMinutes"`
+	Discount                     DiscountBreakdown    `json:"discount"`
+	TotalDistanceKm              float64              `json:"totalDistanceKm"`
+	DepartureAt                  string               `json:"departureAt"`
+	ExpiresAt                    string               `json:"expiresAt"` // batas waktu order
+	Route                        []entities.RouteStop `json:"route"`     // urutan kunjungan, berakhir di lokasi user
+}
+
+// ToEstimateResponse converts an estimate into its API representation
+func ToEstimateResponse(est entities.DeliveryEstimate) (EstimateResponse, error) {
+	res := EstimateResponse{
+		CalculatedEstimateID:         est.ID,
+		TotalPrice:                   est.TotalPrice,
+		EstimatedDeliveryTimeMinutes: fmt.Sprintf("%.2f", est.EstimatedDelivery),
+		Discount:                     ToDiscountBreakdown(est),
+		TotalDistanceKm:              est.TotalDistanceKm,
+		DepartureAt:                  est.DepartureAt.Format(time.RFC3339),
+		ExpiresAt:                    est.ExpiresAt.Format(time.RFC3339),
+		Route:                        []entities.RouteStop{},
+	}
+	if len(est.Route) > 0 {
+		if err := json.Unmarshal(est.Route, &res.Route); err != nil {
+			return EstimateResponse{}, err
+		}
+	}
+	return res, nil
+}
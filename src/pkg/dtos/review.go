@@ -0,0 +1,71 @@
+package dtos
+
+import (
+	"belimang/src/pkg/entities"
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// RatingSummary aggregates the visible reviews of a merchant
+type RatingSummary struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+type ReviewResponse struct {
+	ReviewID   uuid.UUID `json:"reviewId"`
+	MerchantID uuid.UUID `json:"merchantId"`
+	Rating     int       `json:"rating"`
+	Comment    string    `json:"comment"`
+	CreatedAt  string    `json:"createdAt"`
+}
+
+// AdminReviewResponse adds the moderation details shown to admins
+type AdminReviewResponse struct {
+	ReviewResponse
+	OrderID      uuid.UUID `json:"orderId"`
+	UserID       uuid.UUID `json:"userId"`
+	IsHidden     bool      `json:"isHidden"`
+	HiddenReason string    `json:"hiddenReason"`
+}
+
+type ReviewListResponse struct {
+	Data []ReviewResponse `json:"data"`
+	Meta MetaResponse     `json:"meta"`
+}
+
+type AdminReviewListResponse struct {
+	Data []AdminReviewResponse `json:"data"`
+	Meta MetaResponse          `json:"meta"`
+}
+
+// NewRatingSummary rounds the average rating to two decimals
+func NewRatingSummary(average float64, count int) RatingSummary {
+	return RatingSummary{
+		Average: math.Round(average*100) / 100,
+		Count:   count,
+	}
+}
+
+// ToReviewResponse converts a review entity into its public API representation
+func ToReviewResponse(r entities.Review) ReviewResponse {
+	return ReviewResponse{
+		ReviewID:   r.ID,
+		MerchantID: r.MerchantID,
+		Rating:     r.Rating,
+		Comment:    r.Comment,
+		CreatedAt:  FormatNanosToISO8601(r.CreatedAt),
+	}
+}
+
+// ToAdminReviewResponse converts a review entity into its admin API representation
+func ToAdminReviewResponse(r entities.Review) AdminReviewResponse {
+	return AdminReviewResponse{
+		ReviewResponse: ToReviewResponse(r),
+		OrderID:        r.OrderID,
+		UserID:         r.UserID,
+		IsHidden:       r.IsHidden,
+		HiddenReason:   r.HiddenReason,
+	}
+}
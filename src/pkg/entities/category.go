@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	CategoryKindMerchant = "merchant"
+	CategoryKindProduct  = "product"
+)
+
+// Category is a merchant or product category managed by admins
+type Category struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Kind      string    `gorm:"column:kind;not null" json:"kind"`
+	Name      string    `gorm:"column:name;not null" json:"name"`
+	CreatedAt int64     `gorm:"column:created_at;autoCreateTime:nano" json:"createdAt"`
+}
+
+type RequestCategory struct {
+	Kind string `json:"kind" validate:"required,oneof=merchant product"`
+	Name string `json:"name" validate:"required,min=2,max=30"`
+}
+
+type UpdateCategoryRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=30"`
+}
+
+func (Category) TableName() string {
+	return "categories"
+}
+
+func (u *Category) BeforeCreate(tx *gorm.DB) (err error) {
+	if u.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		u.ID = id
+	}
+	return nil
+}
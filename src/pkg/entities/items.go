@@ -1,12 +1,16 @@
 package entities
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type ProductCategory string
 
+// initial categories, the full list is managed in the categories table
 const (
 	Beverage   ProductCategory = "Beverage"
 	Food       ProductCategory = "Food"
@@ -18,20 +22,43 @@ const (
 type Items struct {
 	ID              uuid.UUID       `gorm:"type:uuid;primaryKey" json:"id"`
 	Name            string          `json:"name" gorm:"column:name;not null" validate:"required,min=3,max=30"`
-	ProductCategory ProductCategory `gorm:"column:product_category;not null" json:"productCategory" validate:"required,oneof=Beverage Food Snack Condiments Additions"`
-	Price           float64         `json:"price" gorm:"column:price;type:numeric(10,2);not null" validate:"required,gt=0"`
+	ProductCategory ProductCategory `gorm:"column:product_category;not null" json:"productCategory" validate:"required,product_category"`
+	Price           Money           `json:"price" gorm:"column:price;type:numeric(15,2);not null" validate:"required,gt=0"`
 	ImageUrl        string          `json:"imageUrl" gorm:"column:image_url;not null" validate:"required,url"`
 	MerchantID      uuid.UUID       `json:"merchantId" gorm:"column:merchant_id;not null" validate:"required"`
+	Stock           *int            `json:"stock" gorm:"column:stock"` // nil berarti stok tidak dihitung
+	IsAvailable     bool            `json:"isAvailable" gorm:"column:is_available;not null"`
 	CreatedAt       int64           `gorm:"column:created_at;not null" json:"createdAt"`
+	DeletedAt       gorm.DeletedAt  `gorm:"column:deleted_at;index:idx_items_deleted_at" json:"-"`
 	// MerchantID      uuid.UUID       `gorm:"type:uuid;not null" json:"merchantId"`
-	Merchant Merchant `gorm:"foreignKey:MerchantID;references:ID" json:"-"`
+	Merchant Merchant  `gorm:"foreignKey:MerchantID;references:ID" json:"-"`
+	Tags     []ItemTag `gorm:"foreignKey:ItemID" json:"-"`
+}
+
+// ItemTag labels an item, e.g. halal, vegetarian, spicy or contains-nuts
+type ItemTag struct {
+	ItemID uuid.UUID `gorm:"column:item_id;primaryKey" json:"itemId"`
+	Tag    string    `gorm:"column:tag;primaryKey" json:"tag"`
 }
 
 type RequestItems struct {
 	Name            string          `json:"name" gorm:"column:name;not null" validate:"required,min=3,max=30"`
-	ProductCategory ProductCategory `gorm:"column:product_category;not null" json:"productCategory" validate:"required,oneof=Beverage Food Snack Condiments Additions"`
-	Price           float64         `json:"price" gorm:"column:price;not null" validate:"required,gt=0"`
+	ProductCategory ProductCategory `gorm:"column:product_category;not null" json:"productCategory" validate:"required,product_category"`
+	Price           Money           `json:"price" gorm:"column:price;not null" validate:"required,gt=0"`
 	ImageUrl        string          `json:"imageUrl" gorm:"column:image_url;not null" validate:"required,url"`
+	Stock           *int            `json:"stock" validate:"omitnil,min=0"`
+	IsAvailable     *bool           `json:"isAvailable"`
+	Tags            []string        `json:"tags" validate:"omitempty,max=10,dive,required,max=30"`
+}
+
+type UpdateItemsRequest struct {
+	Name            *string          `json:"name" validate:"omitnil,min=3,max=30"`
+	ProductCategory *ProductCategory `json:"productCategory" validate:"omitnil,product_category"`
+	Price           *Money           `json:"price" validate:"omitnil,gt=0"`
+	ImageUrl        *string          `json:"imageUrl" validate:"omitnil,url"`
+	Stock           *int             `json:"stock" validate:"omitnil,min=0"`
+	IsAvailable     *bool            `json:"isAvailable"`
+	Tags            *[]string        `json:"tags" validate:"omitnil,max=10,dive,required,max=30"`
 }
 
 func (u *Items) BeforeCreate(tx *gorm.DB) (err error) {
@@ -44,3 +71,59 @@ func (u *Items) BeforeCreate(tx *gorm.DB) (err error) {
 	}
 	return nil
 }
+
+func (ItemTag) TableName() string {
+	return "item_tags"
+}
+
+// NormalizeTags lowercases tags, joins words with dashes, drops duplicates and sorts them
+func NormalizeTags(tags []string) []string {
+	seen := map[string]bool{}
+	res := []string{}
+	for _, t := range tags {
+		t = strings.Join(strings.Fields(strings.ToLower(t)), "-")
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		res = append(res, t)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// ToItemTags builds the tag rows of an item, the item ID is filled in when the item is saved
+func ToItemTags(tags []string) []ItemTag {
+	tags = NormalizeTags(tags)
+	res := make([]ItemTag, len(tags))
+	for i, t := range tags {
+		res[i] = ItemTag{Tag: t}
+	}
+	return res
+}
+
+// TagNames lists the tags of an item
+func (u *Items) TagNames() []string {
+	res := make([]string, len(u.Tags))
+	for i, t := range u.Tags {
+		res[i] = t.Tag
+	}
+	return res
+}
+
+// HasTags reports whether the item carries every one of the given tags
+func (u *Items) HasTags(tags []string) bool {
+	for _, want := range tags {
+		found := false
+		for _, t := range u.Tags {
+			if t.Tag == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
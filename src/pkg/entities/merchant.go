@@ -7,6 +7,7 @@ import (
 
 type MerchantCategory string
 
+// initial categories, the full list is managed in the categories table
 const (
 	SmallRestaurant       MerchantCategory = "SmallRestaurant"
 	MediumRestaurant      MerchantCategory = "MediumRestaurant"
@@ -22,8 +23,13 @@ type Merchant struct {
 	ImageUrl         string           `gorm:"column:image_url;not null" json:"imageUrl" validate:"required,url"`
 	Lat              float64          `gorm:"column:lat;not null" json:"lat" validate:"required"`
 	Long             float64          `gorm:"column:long;not null" json:"long" validate:"required"`
-	MerchantCategory MerchantCategory `gorm:"column:merchant_category;not null" json:"merchantCategory" validate:"required,oneof=SmallRestaurant MediumRestaurant LargeRestaurant MerchandiseRestaurant BoothKiosk ConvenienceStore"`
+	MerchantCategory MerchantCategory `gorm:"column:merchant_category;not null" json:"merchantCategory" validate:"required,merchant_category"`
+	OwnerID          *uuid.UUID       `gorm:"column:owner_id;type:uuid" json:"ownerId"`
+	Timezone         string           `gorm:"column:timezone;not null;default:Asia/Jakarta" json:"timezone"`
+	MaxDeliveryKm    float64          `gorm:"column:max_delivery_km;not null;default:3" json:"maxDeliveryKm"`
+	MinOrderValue    Money            `gorm:"column:min_order_value;not null;default:0" json:"minOrderValue"`
 	CreatedAt        int64            `gorm:"column:created_at;not null" json:"createdAt"`
+	DeletedAt        gorm.DeletedAt   `gorm:"column:deleted_at;index:idx_merchants_deleted_at" json:"-"`
 	Items            []Items          `gorm:"foreignKey:MerchantID;references:ID" json:"items"`
 }
 
@@ -36,7 +42,18 @@ type RequestMerchant struct {
 	Name             string           `gorm:"column:name;not null" json:"name" validate:"required,min=3,max=30"`
 	ImageUrl         string           `gorm:"column:image_url;not null" json:"imageUrl" validate:"required,url"`
 	Location         Location         `gorm:"column:name;not null" json:"location" validate:"required"`
-	MerchantCategory MerchantCategory `gorm:"column:merchant_category;not null" json:"merchantCategory" validate:"required,oneof=SmallRestaurant MediumRestaurant LargeRestaurant MerchandiseRestaurant BoothKiosk ConvenienceStore"`
+	MerchantCategory MerchantCategory `gorm:"column:merchant_category;not null" json:"merchantCategory" validate:"required,merchant_category"`
+	MaxDeliveryKm    *float64         `json:"maxDeliveryKm" validate:"omitnil,gt=0"`
+	MinOrderValue    *Money           `json:"minOrderValue" validate:"omitnil,min=0"`
+}
+
+type UpdateMerchantRequest struct {
+	Name             *string           `json:"name" validate:"omitnil,min=3,max=30"`
+	ImageUrl         *string           `json:"imageUrl" validate:"omitnil,url"`
+	Location         *Location         `json:"location" validate:"omitnil"`
+	MerchantCategory *MerchantCategory `json:"merchantCategory" validate:"omitnil,merchant_category"`
+	MaxDeliveryKm    *float64          `json:"maxDeliveryKm" validate:"omitnil,gt=0"`
+	MinOrderValue    *Money            `json:"minOrderValue" validate:"omitnil,min=0"`
 }
 
 func (m Merchant) TableName() string {
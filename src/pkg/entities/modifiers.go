@@ -0,0 +1,78 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ModifierGroup groups the options a customer can pick for an item, e.g. size or add-ons
+type ModifierGroup struct {
+	ID         uuid.UUID        `gorm:"type:uuid;primaryKey" json:"id"`
+	ItemID     uuid.UUID        `gorm:"column:item_id;not null" json:"itemId"`
+	Name       string           `gorm:"column:name;not null" json:"name"`
+	IsRequired bool             `gorm:"column:is_required;not null" json:"isRequired"`
+	MinSelect  int              `gorm:"column:min_select;not null" json:"minSelect"`
+	MaxSelect  int              `gorm:"column:max_select;not null" json:"maxSelect"`
+	CreatedAt  int64            `gorm:"column:created_at;autoCreateTime:nano" json:"createdAt"`
+	Options    []ModifierOption `gorm:"foreignKey:GroupID" json:"options"`
+}
+
+type ModifierOption struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	GroupID    uuid.UUID `gorm:"column:group_id;not null" json:"groupId"`
+	Name       string    `gorm:"column:name;not null" json:"name"`
+	PriceDelta Money     `gorm:"column:price_delta;type:numeric(15,2);not null" json:"priceDelta"`
+	CreatedAt  int64     `gorm:"column:created_at;autoCreateTime:nano" json:"createdAt"`
+}
+
+// SelectedOption is a modifier option chosen for an ordered item, stored as a snapshot in order_items
+type SelectedOption struct {
+	GroupID    uuid.UUID `json:"groupId"`
+	GroupName  string    `json:"groupName"`
+	OptionID   uuid.UUID `json:"optionId"`
+	Name       string    `json:"name"`
+	PriceDelta Money     `json:"priceDelta"`
+}
+
+type RequestModifierGroup struct {
+	Name       string                  `json:"name" validate:"required,min=1,max=30"`
+	IsRequired bool                    `json:"isRequired"`
+	MinSelect  int                     `json:"minSelect" validate:"min=0"`
+	MaxSelect  int                     `json:"maxSelect" validate:"required,min=1"`
+	Options    []RequestModifierOption `json:"options" validate:"required,min=1,dive"`
+}
+
+type RequestModifierOption struct {
+	Name       string `json:"name" validate:"required,min=1,max=30"`
+	PriceDelta Money  `json:"priceDelta"`
+}
+
+func (ModifierGroup) TableName() string {
+	return "item_modifier_groups"
+}
+
+func (ModifierOption) TableName() string {
+	return "item_modifier_options"
+}
+
+func (u *ModifierGroup) BeforeCreate(tx *gorm.DB) (err error) {
+	if u.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		u.ID = id
+	}
+	return nil
+}
+
+func (u *ModifierOption) BeforeCreate(tx *gorm.DB) (err error) {
+	if u.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		u.ID = id
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount in minor units (cents). It is encoded as an exact decimal
+// both in JSON and in numeric columns, so totals never drift through float rounding.
+type Money int64
+
+var ErrInvalidMoney = errors.New("invalid amount: must be a number with at most 2 decimal places")
+
+// MoneyFromFloat converts a float amount, rounding half away from zero to the nearest cent
+func MoneyFromFloat(v float64) Money {
+	return Money(math.Round(v * 100))
+}
+
+// ParseMoney parses a decimal string such as "15000" or "12.50" without going through float64
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if !isDigits(whole) || (hasFrac && !isDigits(frac)) || len(frac) > 2 {
+		return 0, ErrInvalidMoney
+	}
+	frac += strings.Repeat("0", 2-len(frac))
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidMoney
+	}
+	cents, err := strconv.ParseInt(frac, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidMoney
+	}
+	if units > (math.MaxInt64-cents)/100 {
+		return 0, ErrInvalidMoney
+	}
+
+	m := Money(units*100 + cents)
+	if neg {
+		m = -m
+	}
+	return m, nil
+}
+
+// isDigits reports whether s is a non-empty run of ASCII digits, signs are handled by ParseMoney
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Mul multiplies the amount by a quantity
+func (m Money) Mul(qty int) Money {
+	return m * Money(qty)
+}
+
+// Percent returns p percent of the amount, rounded to the nearest cent
+func (m Money) Percent(p float64) Money {
+	return Money(math.Round(float64(m) * p / 100))
+}
+
+// String formats the amount as a decimal, leaving out a zero fraction: 15000, 12.5, 0.05
+func (m Money) String() string {
+	sign := ""
+	v := int64(m)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	units, cents := v/100, v%100
+	switch {
+	case cents == 0:
+		return fmt.Sprintf("%s%d", sign, units)
+	case cents%10 == 0:
+		return fmt.Sprintf("%s%d.%d", sign, units, cents/10)
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, units, cents)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string. Exponent notation is expanded on the
+// digits, so it is only accepted when it is exact to the cent.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	s, err := expandExponent(strings.Trim(s, `"`))
+	if err != nil {
+		return err
+	}
+	v, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// maxExponent bounds exponent notation, amounts with larger exponents never fit in Money
+const maxExponent = 40
+
+// expandExponent rewrites a decimal such as "1.25e3" as "1250" by moving the decimal point.
+// Trailing zeros of the fraction are dropped, so "1.500" becomes "1.5".
+func expandExponent(s string) (string, error) {
+	mantissa, exp := strings.TrimSpace(s), 0
+	if i := strings.IndexAny(mantissa, "eE"); i >= 0 {
+		e, err := strconv.Atoi(mantissa[i+1:])
+		if err != nil || e > maxExponent || e < -maxExponent {
+			return "", ErrInvalidMoney
+		}
+		mantissa, exp = mantissa[:i], e
+	}
+
+	sign := ""
+	if strings.HasPrefix(mantissa, "-") {
+		sign, mantissa = "-", mantissa[1:]
+	}
+	whole, frac, hasFrac := strings.Cut(mantissa, ".")
+	if !isDigits(whole) || (hasFrac && !isDigits(frac)) {
+		return "", ErrInvalidMoney
+	}
+
+	digits := whole + frac
+	point := len(whole) + exp
+	switch {
+	case point <= 0:
+		whole, frac = "0", strings.Repeat("0", -point)+digits
+	case point >= len(digits):
+		whole, frac = digits+strings.Repeat("0", point-len(digits)), ""
+	default:
+		whole, frac = digits[:point], digits[point:]
+	}
+
+	frac = strings.TrimRight(frac, "0")
+	if frac == "" {
+		return sign + whole, nil
+	}
+	return sign + whole + "." + frac, nil
+}
+
+// Value stores the amount as a decimal string so numeric columns receive it exactly
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan reads a numeric column
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case string:
+		return m.scanString(v)
+	case []byte:
+		return m.scanString(string(v))
+	case int64:
+		*m = Money(v * 100)
+		return nil
+	case float64:
+		*m = MoneyFromFloat(v)
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into Money", src)
+}
+
+func (m *Money) scanString(s string) error {
+	// numeric(…,2) selalu punya paling banyak dua digit desimal, kolom tanpa skala dibulatkan
+	// ke sen terdekat langsung pada digitnya, menjauhi nol seperti MoneyFromFloat
+	whole, frac, ok := strings.Cut(s, ".")
+	if !ok || len(frac) <= 2 {
+		v, err := ParseMoney(s)
+		if err != nil {
+			return err
+		}
+		*m = v
+		return nil
+	}
+
+	v, err := ParseMoney(whole + "." + frac[:2])
+	if err != nil || !isDigits(frac[2:]) {
+		return ErrInvalidMoney
+	}
+	if frac[2] >= '5' {
+		if strings.HasPrefix(strings.TrimSpace(whole), "-") {
+			v--
+		} else {
+			v++
+		}
+	}
+	*m = v
+	return nil
+}
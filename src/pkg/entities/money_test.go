@@ -0,0 +1,153 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+		err  bool
+	}{
+		{in: "15000", want: 1500000},
+		{in: "12.5", want: 1250},
+		{in: "12.50", want: 1250},
+		{in: "0.05", want: 5},
+		{in: " 7 ", want: 700},
+		{in: "-12.5", want: -1250},
+		{in: "-0.05", want: -5},
+		{in: "92233720368547758.07", want: 9223372036854775807},
+		{in: "92233720368547758.08", err: true},
+		{in: "100000000000000000000", err: true},
+		{in: "1.005", err: true},
+		{in: "1.", err: true},
+		{in: ".5", err: true},
+		{in: "1.-5", err: true},
+		{in: "1.+5", err: true},
+		{in: "+1", err: true},
+		{in: "--1", err: true},
+		{in: "1e3", err: true},
+		{in: "", err: true},
+		{in: "abc", err: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in)
+		if tt.err {
+			if !errors.Is(err, ErrInvalidMoney) {
+				t.Errorf("ParseMoney(%q) = %v, %v, want ErrInvalidMoney", tt.in, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseMoney(%q) = %d, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		in   Money
+		want string
+	}{
+		{in: 0, want: "0"},
+		{in: 1500000, want: "15000"},
+		{in: 1250, want: "12.5"},
+		{in: 1205, want: "12.05"},
+		{in: 5, want: "0.05"},
+		{in: -1250, want: "-12.5"},
+		{in: -5, want: "-0.05"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Money(%d).String() = %q, want %q", int64(tt.in), got, tt.want)
+		}
+	}
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+		err  bool
+	}{
+		{in: `15000`, want: 1500000},
+		{in: `12.5`, want: 1250},
+		{in: `"12.50"`, want: 1250},
+		{in: `-3`, want: -300},
+		{in: `12.500`, want: 1250},
+		{in: `1.5e3`, want: 150000},
+		{in: `1.5E+3`, want: 150000},
+		{in: `125e-2`, want: 125},
+		{in: `5e-2`, want: 5},
+		{in: `-2.5e1`, want: -2500},
+		{in: `"1e2"`, want: 10000},
+		{in: `0e5`, want: 0},
+		{in: `null`, want: 0},
+		{in: `12.345`, err: true},
+		{in: `0.005`, err: true},
+		{in: `1e-3`, err: true},
+		// di atas 2^53 sen float64 kehilangan presisi, ekspansi pada digit tetap eksak
+		{in: `12345678901234567e-2`, want: 12345678901234567},
+		{in: `123456789012345678e-3`, err: true},
+		{in: `1e30`, err: true},
+		{in: `1e400`, err: true},
+		{in: `92233720368547758.08`, err: true},
+		{in: `1.-5`, err: true},
+		{in: `NaN`, err: true},
+		{in: `true`, err: true},
+		{in: `""`, err: true},
+	}
+	for _, tt := range tests {
+		var got Money
+		err := got.UnmarshalJSON([]byte(tt.in))
+		if tt.err {
+			if !errors.Is(err, ErrInvalidMoney) {
+				t.Errorf("UnmarshalJSON(%s) = %d, %v, want ErrInvalidMoney", tt.in, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("UnmarshalJSON(%s) = %d, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestMoneyScan(t *testing.T) {
+	tests := []struct {
+		name string
+		in   interface{}
+		want Money
+		err  bool
+	}{
+		{name: "null", in: nil, want: 0},
+		{name: "numeric", in: "12.50", want: 1250},
+		{name: "numeric bytes", in: []byte("15000.00"), want: 1500000},
+		{name: "negative numeric", in: "-0.05", want: -5},
+		{name: "integer column", in: int64(3), want: 300},
+		{name: "float column", in: float64(2.5), want: 250},
+		// kolom numeric tanpa skala dibulatkan ke sen terdekat, menjauhi nol
+		{name: "unscaled round down", in: "12.344", want: 1234},
+		{name: "unscaled half up", in: "12.345", want: 1235},
+		{name: "unscaled long fraction", in: "0.004999999", want: 0},
+		{name: "unscaled negative half", in: "-0.005", want: -1},
+		{name: "unscaled carry", in: "9.995", want: 1000},
+		{name: "unscaled invalid", in: "1.23x", err: true},
+		{name: "invalid", in: "abc", err: true},
+		{name: "unsupported type", in: true, err: true},
+	}
+	for _, tt := range tests {
+		var got Money
+		err := got.Scan(tt.in)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%s: Scan(%v) = %d, want an error", tt.name, tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: Scan(%v) = %d, %v, want %d", tt.name, tt.in, got, err, tt.want)
+		}
+	}
+}
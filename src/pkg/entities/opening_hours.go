@@ -0,0 +1,150 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultTimezone is used for merchants whose timezone is missing or unknown
+const DefaultTimezone = "Asia/Jakarta"
+
+// OpeningHours is one opening interval of a merchant's weekly schedule.
+// Minutes are counted from local midnight; a close before open means the
+// interval runs past midnight and open equal to close means open all day.
+type OpeningHours struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	MerchantID  uuid.UUID `gorm:"column:merchant_id;not null" json:"merchantId"`
+	DayOfWeek   int       `gorm:"column:day_of_week;not null" json:"dayOfWeek"` // 0 = Sunday
+	OpenMinute  int       `gorm:"column:open_minute;not null" json:"openMinute"`
+	CloseMinute int       `gorm:"column:close_minute;not null" json:"closeMinute"`
+}
+
+// MerchantClosure closes a merchant for a range of local dates, e.g. a holiday
+type MerchantClosure struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	MerchantID uuid.UUID `gorm:"column:merchant_id;not null" json:"merchantId"`
+	StartDate  time.Time `gorm:"column:start_date;type:date;not null" json:"startDate"`
+	EndDate    time.Time `gorm:"column:end_date;type:date;not null" json:"endDate"`
+	Reason     string    `gorm:"column:reason" json:"reason"`
+}
+
+type RequestOpeningHours struct {
+	Timezone string                     `json:"timezone" validate:"omitempty,timezone"`
+	Hours    []RequestOpeningHoursEntry `json:"hours" validate:"dive"`
+}
+
+type RequestOpeningHoursEntry struct {
+	DayOfWeek *int   `json:"dayOfWeek" validate:"required,min=0,max=6"`
+	Open      string `json:"open" validate:"required,datetime=15:04"`
+	Close     string `json:"close" validate:"required,datetime=15:04"`
+}
+
+type RequestClosure struct {
+	StartDate string `json:"startDate" validate:"required,datetime=2006-01-02"`
+	EndDate   string `json:"endDate" validate:"required,datetime=2006-01-02"`
+	Reason    string `json:"reason" validate:"max=100"`
+}
+
+func (OpeningHours) TableName() string {
+	return "merchant_opening_hours"
+}
+
+func (MerchantClosure) TableName() string {
+	return "merchant_closures"
+}
+
+func (u *OpeningHours) BeforeCreate(tx *gorm.DB) (err error) {
+	if u.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		u.ID = id
+	}
+	return nil
+}
+
+func (u *MerchantClosure) BeforeCreate(tx *gorm.DB) (err error) {
+	if u.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		u.ID = id
+	}
+	return nil
+}
+
+// MerchantLocation resolves a merchant timezone, falling back to DefaultTimezone
+func MerchantLocation(timezone string) *time.Location {
+	if timezone != "" {
+		if loc, err := time.LoadLocation(timezone); err == nil {
+			return loc
+		}
+	}
+	loc, err := time.LoadLocation(DefaultTimezone)
+	if err != nil {
+		return time.FixedZone("WIB", 7*60*60)
+	}
+	return loc
+}
+
+// IsOpenAt reports whether a merchant is open at t. Merchants without any
+// opening hours configured are treated as always open unless closed for the day.
+func IsOpenAt(timezone string, hours []OpeningHours, closures []MerchantClosure, t time.Time) bool {
+	local := t.In(MerchantLocation(timezone))
+
+	today := local.Format(time.DateOnly)
+	for _, c := range closures {
+		if today >= c.StartDate.UTC().Format(time.DateOnly) && today <= c.EndDate.UTC().Format(time.DateOnly) {
+			return false
+		}
+	}
+
+	if len(hours) == 0 {
+		return true
+	}
+
+	minute := local.Hour()*60 + local.Minute()
+	day := int(local.Weekday())
+	yesterday := (day + 6) % 7
+
+	for _, h := range hours {
+		switch {
+		case h.OpenMinute == h.CloseMinute:
+			if h.DayOfWeek == day {
+				return true
+			}
+		case h.OpenMinute < h.CloseMinute:
+			if h.DayOfWeek == day && minute >= h.OpenMinute && minute < h.CloseMinute {
+				return true
+			}
+		default:
+			// buka melewati tengah malam
+			if h.DayOfWeek == day && minute >= h.OpenMinute {
+				return true
+			}
+			if h.DayOfWeek == yesterday && minute < h.CloseMinute {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseClock converts an "HH:MM" string into minutes since midnight
+func ParseClock(v string) (int, error) {
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: must be HH:MM", v)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// FormatClock converts minutes since midnight into an "HH:MM" string
+func FormatClock(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
@@ -2,6 +2,7 @@ package entities
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -17,10 +18,15 @@ type EstimateRequest struct {
 		MerchantID      string `json:"merchantId" validate:"required"`
 		IsStartingPoint bool   `json:"isStartingPoint"`
 		Items           []struct {
-			ItemID   string `json:"itemId" validate:"required"`
-			Quantity int    `json:"quantity" validate:"required,gt=0"`
+			ItemID    string   `json:"itemId" validate:"required"`
+			Quantity  int      `json:"quantity" validate:"required,gt=0"`
+			OptionIDs []string `json:"optionIds"` // modifier option yang dipilih
 		} `json:"items" validate:"required,dive"`
 	} `json:"orders" validate:"required,min=1"`
+	PromoCode string `json:"promoCode" validate:"omitempty,max=30"`
+	// kendaraan dan waktu berangkat untuk estimasi waktu tempuh, default kendaraan bawaan dan sekarang
+	VehicleType   string `json:"vehicleType" validate:"omitempty,max=20"`
+	DepartureTime string `json:"departureTime" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
 }
 
 type OrderRequest struct {
@@ -34,8 +40,9 @@ type OrderWrapper struct {
 }
 
 type OrderItemWrapper struct {
-	ItemID   uuid.UUID `json:"itemId"`
-	Quantity int       `json:"quantity"`
+	ItemID    uuid.UUID   `json:"itemId"`
+	Quantity  int         `json:"quantity"`
+	OptionIDs []uuid.UUID `json:"optionIds"`
 }
 
 type OrderItem struct {
@@ -44,13 +51,20 @@ type OrderItem struct {
 	OrderID    uuid.UUID `json:"orderId" gorm:"column:order_id;not null" validate:"required"`
 	ItemID     uuid.UUID `json:"itemId" gorm:"column:item_id;not null" validate:"required"`
 	Quantity   int       `json:"quantity" gorm:"column:quantity;not null;default:1" validate:"required,gt=0,number"`
+	// snapshot item dan merchant saat order dibuat
+	ItemName        string `json:"itemName" gorm:"column:item_name;not null"`
+	ProductCategory string `json:"productCategory" gorm:"column:product_category;not null"`
+	UnitPrice       Money  `json:"unitPrice" gorm:"column:unit_price;type:numeric(15,2);not null"` // termasuk modifier
+	MerchantName    string `json:"merchantName" gorm:"column:merchant_name;not null"`
+	// snapshot modifier yang dipilih, lihat SelectedOption
+	Options json.RawMessage `json:"options" gorm:"column:options;type:jsonb;not null"`
 	// Relasi ke Order
 	Order Order `gorm:"foreignKey:OrderID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"order,omitempty"`
 }
 
 type Order struct {
 	ID         uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
-	TotalPrice float64   `json:"totalPrice" gorm:"column:total_price;not null" validate:"required,gt=0"`
+	TotalPrice Money     `json:"totalPrice" gorm:"column:total_price;type:numeric(15,2);not null" validate:"required,gt=0"`
 	UserID     uuid.UUID `json:"userId" gorm:"column:user_id;not null"`
 	CreateAt   int64     `json:"createAt" gorm:"column:created_at;not null"`
 	// Relasi ke OrderItem
@@ -61,8 +75,32 @@ type DeliveryEstimate struct {
 	ID                uuid.UUID       `json:"id" gorm:"type:uuid;primaryKey"`
 	UserID            uuid.UUID       `json:"userId" gorm:"column:user_id;not null"`
 	Orders            json.RawMessage `json:"orders" gorm:"column:orders;not null" validate:"required"`
-	TotalPrice        float64         `json:"totalPrice" gorm:"column:total_price;not null" validate:"required,gt=0"`
+	TotalPrice        Money           `json:"totalPrice" gorm:"column:total_price;type:numeric(15,2);not null" validate:"required,gt=0"`
 	EstimatedDelivery float64         `json:"estimatedDelivery" gorm:"column:estimated_delivery_time_minutes;not null" validate:"required"`
+	CreatedAt         time.Time       `json:"createdAt" gorm:"column:created_at;not null"` // kolom tanpa zona waktu, disimpan dalam UTC
+	PromotionDiscount Money           `json:"promotionDiscount" gorm:"column:promotion_discount;not null"`
+	PromoCode         string          `json:"promoCode" gorm:"column:promo_code;not null"`
+	PromoCodeDiscount Money           `json:"promoCodeDiscount" gorm:"column:promo_code_discount;not null"`
+	// rute pengantaran, lihat RouteStop
+	Route           json.RawMessage `json:"route" gorm:"column:route;type:jsonb;not null"`
+	TotalDistanceKm float64         `json:"totalDistanceKm" gorm:"column:total_distance_km;not null"`
+	DepartureAt     time.Time       `json:"departureAt" gorm:"column:departure_at;not null"` // UTC
+	ExpiresAt       time.Time       `json:"expiresAt" gorm:"column:expires_at;not null"`     // UTC
+	ConsumedAt      *time.Time      `json:"consumedAt" gorm:"column:consumed_at"`            // terisi setelah di-order
+	// order_items dengan harga saat estimasi, ditulis apa adanya selama harga estimasi dikunci
+	PricedItems json.RawMessage `json:"-" gorm:"column:priced_items;type:jsonb;not null"`
+}
+
+// RouteStop is a stop of a planned delivery route together with the leg that reaches it.
+// The first stop is the starting merchant, the last one is the user location.
+type RouteStop struct {
+	MerchantID     *uuid.UUID `json:"merchantId"` // nil untuk lokasi user
+	Name           string     `json:"name"`
+	Location       Location   `json:"location"`
+	DistanceKm     float64    `json:"distanceKm"`     // leg dari stop sebelumnya
+	Minutes        float64    `json:"minutes"`        // waktu tempuh leg
+	ArrivalMinutes float64    `json:"arrivalMinutes"` // kumulatif sejak berangkat
+	ArrivalAt      time.Time  `json:"arrivalAt"`
 }
 
 func (u *Order) BeforeCreate(tx *gorm.DB) (err error) {
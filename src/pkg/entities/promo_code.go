@@ -0,0 +1,128 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PromoCode is a code users enter at checkout for a discount on their basket
+type PromoCode struct {
+	ID              uuid.UUID           `gorm:"type:uuid;primaryKey" json:"id"`
+	Code            string              `gorm:"column:code;not null" json:"code"`
+	DiscountType    string              `gorm:"column:discount_type;not null" json:"discountType"`
+	DiscountPercent float64             `gorm:"column:discount_percent;type:numeric(5,2);not null" json:"discountPercent"`
+	DiscountAmount  Money               `gorm:"column:discount_amount;type:numeric(15,2);not null" json:"discountAmount"`
+	MinSpend        Money               `gorm:"column:min_spend;type:numeric(15,2);not null" json:"minSpend"`
+	MaxDiscount     *Money              `gorm:"column:max_discount;type:numeric(15,2)" json:"maxDiscount"`
+	UsageLimit      *int                `gorm:"column:usage_limit" json:"usageLimit"`
+	PerUserLimit    *int                `gorm:"column:per_user_limit" json:"perUserLimit"`
+	StartsAt        time.Time           `gorm:"column:starts_at;not null" json:"startsAt"`
+	EndsAt          time.Time           `gorm:"column:ends_at;not null" json:"endsAt"`
+	IsActive        bool                `gorm:"column:is_active;not null" json:"isActive"`
+	CreatedBy       uuid.UUID           `gorm:"column:created_by;not null" json:"createdBy"`
+	CreatedAt       int64               `gorm:"column:created_at;autoCreateTime:nano" json:"createdAt"`
+	Merchants       []PromoCodeMerchant `gorm:"foreignKey:PromoCodeID" json:"-"`
+}
+
+// PromoCodeMerchant restricts a promo code to a merchant, codes without any are valid everywhere
+type PromoCodeMerchant struct {
+	PromoCodeID uuid.UUID `gorm:"column:promo_code_id;primaryKey" json:"promoCodeId"`
+	MerchantID  uuid.UUID `gorm:"column:merchant_id;primaryKey" json:"merchantId"`
+}
+
+// PromoCodeRedemption records a promo code used by an order
+type PromoCodeRedemption struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	PromoCodeID uuid.UUID `gorm:"column:promo_code_id;not null" json:"promoCodeId"`
+	UserID      uuid.UUID `gorm:"column:user_id;not null" json:"userId"`
+	OrderID     uuid.UUID `gorm:"column:order_id;not null" json:"orderId"`
+	Discount    Money     `gorm:"column:discount;type:numeric(15,2);not null" json:"discount"`
+	CreatedAt   int64     `gorm:"column:created_at;autoCreateTime:nano" json:"createdAt"`
+}
+
+type RequestPromoCode struct {
+	Code            string   `json:"code" validate:"required,min=3,max=30,alphanum"`
+	DiscountType    string   `json:"discountType" validate:"required,oneof=percent fixed"`
+	DiscountPercent float64  `json:"discountPercent" validate:"required_if=DiscountType percent,excluded_unless=DiscountType percent,omitempty,gt=0,lte=100"`
+	DiscountAmount  Money    `json:"discountAmount" validate:"required_if=DiscountType fixed,excluded_unless=DiscountType fixed,omitempty,gt=0"`
+	MinSpend        Money    `json:"minSpend" validate:"min=0"`
+	MaxDiscount     *Money   `json:"maxDiscount" validate:"omitnil,gt=0"`
+	UsageLimit      *int     `json:"usageLimit" validate:"omitnil,gt=0"`
+	PerUserLimit    *int     `json:"perUserLimit" validate:"omitnil,gt=0"`
+	StartsAt        string   `json:"startsAt" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	EndsAt          string   `json:"endsAt" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	MerchantIDs     []string `json:"merchantIds" validate:"dive,uuid"`
+}
+
+func (PromoCode) TableName() string {
+	return "promo_codes"
+}
+
+func (PromoCodeMerchant) TableName() string {
+	return "promo_code_merchants"
+}
+
+func (PromoCodeRedemption) TableName() string {
+	return "promo_code_redemptions"
+}
+
+func (u *PromoCode) BeforeCreate(tx *gorm.DB) (err error) {
+	if u.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		u.ID = id
+	}
+	return nil
+}
+
+func (u *PromoCodeRedemption) BeforeCreate(tx *gorm.DB) (err error) {
+	if u.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		u.ID = id
+	}
+	return nil
+}
+
+// NormalizePromoCode makes codes case insensitive
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// ValidAt reports whether the code is active and t falls within its validity dates
+func (u *PromoCode) ValidAt(t time.Time) bool {
+	return u.IsActive && !t.Before(u.StartsAt) && t.Before(u.EndsAt)
+}
+
+// AllowsMerchant reports whether the code may be used for the merchant
+func (u *PromoCode) AllowsMerchant(merchantId uuid.UUID) bool {
+	if len(u.Merchants) == 0 {
+		return true
+	}
+	for _, m := range u.Merchants {
+		if m.MerchantID == merchantId {
+			return true
+		}
+	}
+	return false
+}
+
+// Discount returns the amount taken off the eligible spend, capped by MaxDiscount and the spend itself.
+// DiscountPercent or DiscountAmount applies depending on DiscountType.
+func (u *PromoCode) Discount(spend Money) Money {
+	discount := u.DiscountAmount
+	if u.DiscountType == DiscountPercent {
+		discount = spend.Percent(u.DiscountPercent)
+	}
+	if u.MaxDiscount != nil {
+		discount = min(discount, *u.MaxDiscount)
+	}
+	return min(discount, spend)
+}
@@ -0,0 +1,151 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	PromotionScopeMerchant = "merchant"
+	PromotionScopeCategory = "category"
+	PromotionScopeItems    = "items"
+
+	DiscountPercent = "percent"
+	DiscountFixed   = "fixed"
+)
+
+// Promotion discounts the items of a merchant between StartsAt and EndsAt, optionally
+// only during a daily window counted in minutes from local midnight of the merchant.
+type Promotion struct {
+	ID               uuid.UUID       `gorm:"type:uuid;primaryKey" json:"id"`
+	MerchantID       uuid.UUID       `gorm:"column:merchant_id;not null" json:"merchantId"`
+	Name             string          `gorm:"column:name;not null" json:"name"`
+	Scope            string          `gorm:"column:scope;not null" json:"scope"`
+	ProductCategory  ProductCategory `gorm:"column:product_category;not null" json:"productCategory"`
+	DiscountType     string          `gorm:"column:discount_type;not null" json:"discountType"`
+	DiscountPercent  float64         `gorm:"column:discount_percent;type:numeric(5,2);not null" json:"discountPercent"`
+	DiscountAmount   Money           `gorm:"column:discount_amount;type:numeric(15,2);not null" json:"discountAmount"`
+	StartsAt         time.Time       `gorm:"column:starts_at;not null" json:"startsAt"`
+	EndsAt           time.Time       `gorm:"column:ends_at;not null" json:"endsAt"`
+	DailyStartMinute *int            `gorm:"column:daily_start_minute" json:"dailyStartMinute"`
+	DailyEndMinute   *int            `gorm:"column:daily_end_minute" json:"dailyEndMinute"`
+	CreatedAt        int64           `gorm:"column:created_at;autoCreateTime:nano" json:"createdAt"`
+	Items            []PromotionItem `gorm:"foreignKey:PromotionID" json:"-"`
+}
+
+type PromotionItem struct {
+	PromotionID uuid.UUID `gorm:"column:promotion_id;primaryKey" json:"promotionId"`
+	ItemID      uuid.UUID `gorm:"column:item_id;primaryKey" json:"itemId"`
+}
+
+type RequestPromotion struct {
+	Name            string          `json:"name" validate:"required,min=2,max=50"`
+	Scope           string          `json:"scope" validate:"required,oneof=merchant category items"`
+	ProductCategory ProductCategory `json:"productCategory" validate:"required_if=Scope category,omitempty,product_category"`
+	ItemIDs         []string        `json:"itemIds" validate:"required_if=Scope items,dive,uuid"`
+	DiscountType    string          `json:"discountType" validate:"required,oneof=percent fixed"`
+	DiscountPercent float64         `json:"discountPercent" validate:"required_if=DiscountType percent,excluded_unless=DiscountType percent,omitempty,gt=0,lte=100"`
+	DiscountAmount  Money           `json:"discountAmount" validate:"required_if=DiscountType fixed,excluded_unless=DiscountType fixed,omitempty,gt=0"`
+	StartsAt        string          `json:"startsAt" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	EndsAt          string          `json:"endsAt" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	DailyStart      string          `json:"dailyStart" validate:"required_with=DailyEnd,omitempty,datetime=15:04"`
+	DailyEnd        string          `json:"dailyEnd" validate:"required_with=DailyStart,omitempty,datetime=15:04"`
+}
+
+func (Promotion) TableName() string {
+	return "promotions"
+}
+
+func (PromotionItem) TableName() string {
+	return "promotion_items"
+}
+
+func (u *Promotion) BeforeCreate(tx *gorm.DB) (err error) {
+	if u.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		u.ID = id
+	}
+	return nil
+}
+
+// ActiveAt reports whether the promotion runs at t for a merchant in the given timezone
+func (u *Promotion) ActiveAt(timezone string, t time.Time) bool {
+	if t.Before(u.StartsAt) || !t.Before(u.EndsAt) {
+		return false
+	}
+	if u.DailyStartMinute == nil || u.DailyEndMinute == nil || *u.DailyStartMinute == *u.DailyEndMinute {
+		return true
+	}
+
+	local := t.In(MerchantLocation(timezone))
+	minute := local.Hour()*60 + local.Minute()
+	start, end := *u.DailyStartMinute, *u.DailyEndMinute
+	if start < end {
+		return minute >= start && minute < end
+	}
+	// jendela melewati tengah malam
+	return minute >= start || minute < end
+}
+
+// AppliesTo reports whether the item falls within the scope of the promotion
+func (u *Promotion) AppliesTo(item Items) bool {
+	if item.MerchantID != u.MerchantID {
+		return false
+	}
+	switch u.Scope {
+	case PromotionScopeMerchant:
+		return true
+	case PromotionScopeCategory:
+		return item.ProductCategory == u.ProductCategory
+	case PromotionScopeItems:
+		for _, i := range u.Items {
+			if i.ItemID == item.ID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ValidDiscount reports whether only the discount field of the given type is set, within its range
+func ValidDiscount(discountType string, percent float64, amount Money) bool {
+	switch discountType {
+	case DiscountPercent:
+		return percent > 0 && percent <= 100 && amount == 0
+	case DiscountFixed:
+		return amount > 0 && percent == 0
+	}
+	return false
+}
+
+// Discount returns the amount taken off a price, never more than the price itself.
+// DiscountPercent or DiscountAmount applies depending on DiscountType.
+func (u *Promotion) Discount(price Money) Money {
+	discount := u.DiscountAmount
+	if u.DiscountType == DiscountPercent {
+		discount = price.Percent(u.DiscountPercent)
+	}
+	return min(discount, price)
+}
+
+// EffectivePrice applies the best promotion running at t to the item price.
+// Promotions do not stack, the returned promotion is nil when none applies.
+func EffectivePrice(item Items, timezone string, promotions []Promotion, t time.Time) (Money, *Promotion) {
+	var best *Promotion
+	var bestDiscount Money
+	for i := range promotions {
+		p := &promotions[i]
+		if !p.AppliesTo(item) || !p.ActiveAt(timezone, t) {
+			continue
+		}
+		if d := p.Discount(item.Price); d > bestDiscount {
+			best, bestDiscount = p, d
+		}
+	}
+	return item.Price - bestDiscount, best
+}
@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Review is a user's rating of a merchant for one of their orders
+type Review struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	OrderID      uuid.UUID `gorm:"column:order_id;not null" json:"orderId"`
+	MerchantID   uuid.UUID `gorm:"column:merchant_id;not null" json:"merchantId"`
+	UserID       uuid.UUID `gorm:"column:user_id;not null" json:"userId"`
+	Rating       int       `gorm:"column:rating;not null" json:"rating"`
+	Comment      string    `gorm:"column:comment" json:"comment"`
+	IsHidden     bool      `gorm:"column:is_hidden;not null" json:"isHidden"`
+	HiddenReason string    `gorm:"column:hidden_reason" json:"hiddenReason"`
+	CreatedAt    int64     `gorm:"column:created_at;autoCreateTime:nano" json:"createdAt"`
+}
+
+type RequestReview struct {
+	OrderID string `json:"orderId" validate:"required,uuid"`
+	Rating  int    `json:"rating" validate:"required,min=1,max=5"`
+	Comment string `json:"comment" validate:"max=500"`
+}
+
+type ModerateReviewRequest struct {
+	IsHidden *bool  `json:"isHidden" validate:"required"`
+	Reason   string `json:"reason" validate:"max=200"`
+}
+
+func (Review) TableName() string {
+	return "reviews"
+}
+
+func (u *Review) BeforeCreate(tx *gorm.DB) (err error) {
+	if u.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		u.ID = id
+	}
+	return nil
+}
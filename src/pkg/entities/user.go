@@ -10,6 +10,9 @@ import (
 const (
 	RoleUser  = "user"
 	RoleAdmin = "admin"
+	// RoleSuperAdmin can manage every merchant regardless of owner.
+	// It is granted directly in the database and logs in through the admin endpoints.
+	RoleSuperAdmin = "superadmin"
 )
 
 type User struct {
@@ -0,0 +1,211 @@
+package merchant
+
+import (
+	"belimang/src/pkg/dtos"
+	"belimang/src/pkg/entities"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+)
+
+const (
+	ExportFormatCSV     = "csv"
+	ExportFormatNDJSON  = "ndjson"
+	ExportFormatGeoJSON = "geojson"
+)
+
+var ErrUnsupportedExportFormat = errors.New("unsupported export format: must be csv, ndjson or geojson")
+
+// ExportContentType returns the content type of the given export format
+func ExportContentType(format string) (string, error) {
+	switch format {
+	case ExportFormatCSV:
+		return "text/csv", nil
+	case ExportFormatNDJSON:
+		return "application/x-ndjson", nil
+	case ExportFormatGeoJSON:
+		return "application/geo+json", nil
+	default:
+		return "", ErrUnsupportedExportFormat
+	}
+}
+
+// CatalogExport is an opened catalog export, see Service.ExportCatalog
+type CatalogExport struct {
+	format string
+	cursor CatalogCursor
+}
+
+// Stream writes the catalog to w one merchant at a time
+func (e *CatalogExport) Stream(w io.Writer) error {
+	cw, err := newCatalogWriter(w, e.format)
+	if err != nil {
+		return err
+	}
+	if err := e.cursor.Each(cw.WriteMerchant); err != nil {
+		return err
+	}
+	return cw.Close()
+}
+
+// Close releases the catalog query
+func (e *CatalogExport) Close() error {
+	return e.cursor.Close()
+}
+
+// catalogWriter writes merchants one at a time so the catalog never has to be held in memory
+type catalogWriter interface {
+	WriteMerchant(m entities.Merchant, items []entities.Items) error
+	Close() error
+}
+
+func newCatalogWriter(w io.Writer, format string) (catalogWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		return newCSVCatalogWriter(w)
+	case ExportFormatNDJSON:
+		return &ndjsonCatalogWriter{enc: json.NewEncoder(w)}, nil
+	case ExportFormatGeoJSON:
+		return &geojsonCatalogWriter{w: w}, nil
+	default:
+		return nil, ErrUnsupportedExportFormat
+	}
+}
+
+// csvCatalogWriter writes one row per item using the same columns accepted by the import,
+// merchants without items get a single row with empty item columns
+type csvCatalogWriter struct {
+	w *csv.Writer
+}
+
+func newCSVCatalogWriter(w io.Writer) (*csvCatalogWriter, error) {
+	cw := csv.NewWriter(w)
+	header := append([]string{"merchantId"}, csvColumns[:5]...)
+	header = append(header, "itemId")
+	header = append(header, csvColumns[5:]...)
+	if err := cw.Write(header); err != nil {
+		return nil, err
+	}
+	return &csvCatalogWriter{w: cw}, nil
+}
+
+func (c *csvCatalogWriter) WriteMerchant(m entities.Merchant, items []entities.Items) error {
+	merchantCols := []string{
+		m.ID.String(),
+		m.Name,
+		m.ImageUrl,
+		strconv.FormatFloat(m.Lat, 'f', -1, 64),
+		strconv.FormatFloat(m.Long, 'f', -1, 64),
+		string(m.MerchantCategory),
+	}
+
+	if len(items) == 0 {
+		return c.w.Write(append(merchantCols, "", "", "", "", ""))
+	}
+
+	for _, item := range items {
+		record := append(append([]string{}, merchantCols...),
+			item.ID.String(),
+			item.Name,
+			string(item.ProductCategory),
+			item.Price.String(),
+			item.ImageUrl,
+		)
+		if err := c.w.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *csvCatalogWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type exportMerchant struct {
+	dtos.MerchantResponse
+	Items []dtos.ItemResponse `json:"items"`
+}
+
+// ndjsonCatalogWriter writes one merchant with its items per line
+type ndjsonCatalogWriter struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonCatalogWriter) WriteMerchant(m entities.Merchant, items []entities.Items) error {
+	line := exportMerchant{
+		MerchantResponse: dtos.ToMerchantResponse(m),
+		Items:            make([]dtos.ItemResponse, len(items)),
+	}
+	for i, item := range items {
+		line.Items[i] = dtos.ToItemResponse(item)
+	}
+	return n.enc.Encode(line)
+}
+
+func (n *ndjsonCatalogWriter) Close() error {
+	return nil
+}
+
+type geojsonGeometry struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+type geojsonFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geojsonGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// geojsonCatalogWriter writes a FeatureCollection of merchant points
+type geojsonCatalogWriter struct {
+	w       io.Writer
+	started bool
+}
+
+func (g *geojsonCatalogWriter) WriteMerchant(m entities.Merchant, items []entities.Items) error {
+	prefix := ","
+	if !g.started {
+		prefix = `{"type":"FeatureCollection","features":[`
+		g.started = true
+	}
+
+	feature, err := json.Marshal(geojsonFeature{
+		Type: "Feature",
+		Geometry: geojsonGeometry{
+			Type: "Point",
+			// GeoJSON memakai urutan [longitude, latitude]
+			Coordinates: [2]float64{m.Long, m.Lat},
+		},
+		Properties: map[string]interface{}{
+			"merchantId":       m.ID,
+			"name":             m.Name,
+			"merchantCategory": m.MerchantCategory,
+			"imageUrl":         m.ImageUrl,
+			"itemCount":        len(items),
+			"createdAt":        dtos.FormatNanosToISO8601(m.CreatedAt),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(g.w, prefix); err != nil {
+		return err
+	}
+	_, err = g.w.Write(feature)
+	return err
+}
+
+func (g *geojsonCatalogWriter) Close() error {
+	if !g.started {
+		_, err := io.WriteString(g.w, `{"type":"FeatureCollection","features":[]}`)
+		return err
+	}
+	_, err := io.WriteString(g.w, "]}")
+	return err
+}
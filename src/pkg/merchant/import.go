@@ -0,0 +1,251 @@
+package merchant
+
+import (
+	"belimang/src/pkg/category"
+	"belimang/src/pkg/dtos"
+	"belimang/src/pkg/entities"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+)
+
+var (
+	ErrUnsupportedImportFormat = errors.New("unsupported import format: must be csv or ndjson")
+	ErrInvalidImportFile       = errors.New("invalid import file")
+)
+
+// newValidator creates the validator shared by the admin endpoints and imports.
+// Fields are reported by their json name and categories are checked against the database.
+func newValidator(categories category.Service) *validator.Validate {
+	v := dtos.NewValidator()
+	category.RegisterValidations(v, categories)
+	return v
+}
+
+// ImportRowError describes why a row of an import file was rejected.
+// Path points at the nested part of the row that failed, e.g. "items[1]".
+type ImportRowError struct {
+	Row  int
+	Path string
+	Err  error
+}
+
+// ImportResult summarizes an import run
+type ImportResult struct {
+	DryRun    bool
+	Merchants int
+	Items     int
+	Errors    []ImportRowError
+}
+
+type importItem struct {
+	row  int
+	path string
+	data entities.RequestItems
+}
+
+type importMerchant struct {
+	row   int
+	data  entities.RequestMerchant
+	items []importItem
+}
+
+type ndjsonMerchant struct {
+	entities.RequestMerchant
+	Items []entities.RequestItems `json:"items"`
+}
+
+// csvColumns lists the supported csv header columns. Each row holds one menu item,
+// rows repeating the same merchant columns are grouped into one merchant.
+var csvColumns = []string{
+	"name", "imageUrl", "lat", "long", "merchantCategory",
+	"itemName", "itemProductCategory", "itemPrice", "itemImageUrl",
+}
+
+// parseImport reads merchants with their items from the given reader
+func parseImport(r io.Reader, format string) ([]importMerchant, []ImportRowError, error) {
+	switch format {
+	case ImportFormatCSV:
+		return parseCSV(r)
+	case ImportFormatNDJSON:
+		return parseNDJSON(r)
+	default:
+		return nil, nil, ErrUnsupportedImportFormat
+	}
+}
+
+func parseNDJSON(r io.Reader) ([]importMerchant, []ImportRowError, error) {
+	var merchants []importMerchant
+	var rowErrors []ImportRowError
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	row := 0
+	for scanner.Scan() {
+		row++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var m ndjsonMerchant
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			rowErrors = append(rowErrors, ImportRowError{Row: row, Err: fmt.Errorf("invalid json: %w", err)})
+			continue
+		}
+
+		im := importMerchant{row: row, data: m.RequestMerchant}
+		for i, item := range m.Items {
+			im.items = append(im.items, importItem{
+				row:  row,
+				path: fmt.Sprintf("items[%d]", i),
+				data: item,
+			})
+		}
+		merchants = append(merchants, im)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+
+	return merchants, rowErrors, nil
+}
+
+func parseCSV(r io.Reader) ([]importMerchant, []ImportRowError, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: failed to read csv header: %v", ErrInvalidImportFile, err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		index[strings.TrimSpace(h)] = i
+	}
+	for _, col := range csvColumns[:5] {
+		if _, ok := index[col]; !ok {
+			return nil, nil, fmt.Errorf("%w: csv header is missing column %q", ErrInvalidImportFile, col)
+		}
+	}
+
+	var merchants []importMerchant
+	var rowErrors []ImportRowError
+	byKey := make(map[string]int)
+
+	row := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		row++
+		if err != nil {
+			rowErrors = append(rowErrors, ImportRowError{Row: row, Err: err})
+			continue
+		}
+
+		get := func(col string) string {
+			if i, ok := index[col]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		lat, errLat := parseCSVFloat(get("lat"))
+		long, errLong := parseCSVFloat(get("long"))
+		if errLat != nil || errLong != nil {
+			rowErrors = append(rowErrors, ImportRowError{Row: row, Err: errors.New("lat/long is not valid")})
+			continue
+		}
+
+		key := strings.Join([]string{get("name"), get("imageUrl"), get("lat"), get("long"), get("merchantCategory")}, "\x00")
+		idx, ok := byKey[key]
+		if !ok {
+			merchants = append(merchants, importMerchant{
+				row: row,
+				data: entities.RequestMerchant{
+					Name:             get("name"),
+					ImageUrl:         get("imageUrl"),
+					Location:         entities.Location{Lat: lat, Long: long},
+					MerchantCategory: entities.MerchantCategory(get("merchantCategory")),
+				},
+			})
+			idx = len(merchants) - 1
+			byKey[key] = idx
+		}
+
+		// baris tanpa kolom item hanya mendaftarkan merchant
+		if get("itemName") == "" && get("itemProductCategory") == "" && get("itemPrice") == "" && get("itemImageUrl") == "" {
+			continue
+		}
+
+		price, err := entities.ParseMoney(get("itemPrice"))
+		if err != nil {
+			rowErrors = append(rowErrors, ImportRowError{Row: row, Path: "item", Err: errors.New("price is not valid")})
+			continue
+		}
+
+		merchants[idx].items = append(merchants[idx].items, importItem{
+			row:  row,
+			path: "item",
+			data: entities.RequestItems{
+				Name:            get("itemName"),
+				ProductCategory: entities.ProductCategory(get("itemProductCategory")),
+				Price:           price,
+				ImageUrl:        get("itemImageUrl"),
+			},
+		})
+	}
+
+	return merchants, rowErrors, nil
+}
+
+func parseCSVFloat(v string) (float64, error) {
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(v, 64)
+}
+
+// validateImport validates every merchant and item, collecting errors per row.
+// It stops with an error when a row could not be validated at all.
+func validateImport(validate *validator.Validate, merchants []importMerchant) ([]ImportRowError, error) {
+	var rowErrors []ImportRowError
+	check := func(row int, path string, data interface{}) error {
+		err := category.Validate(validate, data)
+		var fieldErrors validator.ValidationErrors
+		if err != nil && !errors.As(err, &fieldErrors) {
+			return err
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, ImportRowError{Row: row, Path: path, Err: err})
+		}
+		return nil
+	}
+	for _, m := range merchants {
+		if err := check(m.row, "", m.data); err != nil {
+			return nil, err
+		}
+		for _, item := range m.items {
+			if err := check(item.row, item.path, item.data); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return rowErrors, nil
+}
@@ -0,0 +1,116 @@
+package merchant
+
+import (
+	"belimang/src/pkg/dtos"
+	"belimang/src/pkg/entities"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Queries shared with the repositories of other packages that show merchants, so the admin
+// and the user facing endpoints read the same data the same way.
+
+// FindRatings aggregates the visible reviews of the given merchants keyed by merchant ID
+func FindRatings(db *gorm.DB, merchantIds []uuid.UUID) (map[uuid.UUID]dtos.RatingSummary, error) {
+	ratings := map[uuid.UUID]dtos.RatingSummary{}
+	if len(merchantIds) == 0 {
+		return ratings, nil
+	}
+
+	var rows []struct {
+		MerchantID uuid.UUID
+		Average    float64
+		Count      int
+	}
+	err := db.
+		Table("reviews").
+		Select("merchant_id, AVG(rating) AS average, COUNT(*) AS count").
+		Where("merchant_id IN ? AND NOT is_hidden", merchantIds).
+		Group("merchant_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		ratings[row.MerchantID] = dtos.NewRatingSummary(row.Average, row.Count)
+	}
+	return ratings, nil
+}
+
+// FindActivePromotions loads the promotions of the given merchants whose date range contains t,
+// keyed by merchant ID. Daily windows are left to Promotion.ActiveAt.
+func FindActivePromotions(db *gorm.DB, merchantIds []uuid.UUID, t time.Time) (map[uuid.UUID][]entities.Promotion, error) {
+	promotionsByMerchant := map[uuid.UUID][]entities.Promotion{}
+	if len(merchantIds) == 0 {
+		return promotionsByMerchant, nil
+	}
+
+	var promotions []entities.Promotion
+	err := db.
+		Preload("Items").
+		Where("merchant_id IN ? AND starts_at <= ? AND ends_at > ?", merchantIds, t, t).
+		Find(&promotions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range promotions {
+		promotionsByMerchant[p.MerchantID] = append(promotionsByMerchant[p.MerchantID], p)
+	}
+	return promotionsByMerchant, nil
+}
+
+// FindSchedules loads the opening hours and closures of the given merchants keyed by merchant ID
+func FindSchedules(db *gorm.DB, merchantIds []uuid.UUID) (map[uuid.UUID][]entities.OpeningHours, map[uuid.UUID][]entities.MerchantClosure, error) {
+	hours := map[uuid.UUID][]entities.OpeningHours{}
+	closures := map[uuid.UUID][]entities.MerchantClosure{}
+	if len(merchantIds) == 0 {
+		return hours, closures, nil
+	}
+
+	var openingHours []entities.OpeningHours
+	if err := db.Where("merchant_id IN ?", merchantIds).Order("day_of_week, open_minute").Find(&openingHours).Error; err != nil {
+		return nil, nil, err
+	}
+	for _, h := range openingHours {
+		hours[h.MerchantID] = append(hours[h.MerchantID], h)
+	}
+
+	var merchantClosures []entities.MerchantClosure
+	if err := db.Where("merchant_id IN ?", merchantIds).Order("start_date").Find(&merchantClosures).Error; err != nil {
+		return nil, nil, err
+	}
+	for _, c := range merchantClosures {
+		closures[c.MerchantID] = append(closures[c.MerchantID], c)
+	}
+
+	return hours, closures, nil
+}
+
+// FindModifierGroups loads the modifier groups with their options of the given items keyed by item ID
+func FindModifierGroups(db *gorm.DB, itemIds []uuid.UUID) (map[uuid.UUID][]entities.ModifierGroup, error) {
+	groupsByItem := map[uuid.UUID][]entities.ModifierGroup{}
+	if len(itemIds) == 0 {
+		return groupsByItem, nil
+	}
+
+	var groups []entities.ModifierGroup
+	err := db.
+		Preload("Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at")
+		}).
+		Where("item_id IN ?", itemIds).
+		Order("created_at").
+		Find(&groups).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, g := range groups {
+		groupsByItem[g.ItemID] = append(groupsByItem[g.ItemID], g)
+	}
+	return groupsByItem, nil
+}
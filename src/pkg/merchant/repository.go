@@ -1,8 +1,14 @@
 package merchant
 
 import (
+	"belimang/src/pkg/dtos"
 	"belimang/src/pkg/entities"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -10,6 +16,28 @@ import (
 type Repository interface {
 	CreateMerchant(merchant *entities.Merchant) (*entities.Merchant, error)
 	CreateItems(items *entities.Items) (*entities.Items, error)
+	FindMerchants(params map[string]interface{}) ([]entities.Merchant, int64, error)
+	FindMerchantById(merchantId uuid.UUID) (*entities.Merchant, error)
+	FindItems(merchantId uuid.UUID, params map[string]interface{}) ([]entities.Items, int64, error)
+	FindItemById(merchantId, itemId uuid.UUID) (*entities.Items, error)
+	UpdateMerchant(merchant *entities.Merchant) (*entities.Merchant, error)
+	UpdateItems(items *entities.Items) (*entities.Items, error)
+	DeleteMerchant(merchantId uuid.UUID) error
+	DeleteItems(itemId uuid.UUID) error
+	ImportMerchants(merchants []entities.Merchant) error
+	OpenCatalog(params map[string]interface{}) (CatalogCursor, error)
+	FindRatings(merchantIds []uuid.UUID) (map[uuid.UUID]dtos.RatingSummary, error)
+	FindSchedules(merchantIds []uuid.UUID) (map[uuid.UUID][]entities.OpeningHours, map[uuid.UUID][]entities.MerchantClosure, error)
+	ReplaceOpeningHours(merchantId uuid.UUID, timezone string, hours []entities.OpeningHours) error
+	CreateClosure(closure *entities.MerchantClosure) (*entities.MerchantClosure, error)
+	DeleteClosure(merchantId, closureId uuid.UUID) (int64, error)
+	FindModifierGroups(itemIds []uuid.UUID) (map[uuid.UUID][]entities.ModifierGroup, error)
+	CreateModifierGroup(group *entities.ModifierGroup) (*entities.ModifierGroup, error)
+	DeleteModifierGroup(itemId, groupId uuid.UUID) (int64, error)
+	FindPromotions(merchantId uuid.UUID) ([]entities.Promotion, error)
+	FindActivePromotions(merchantIds []uuid.UUID, t time.Time) (map[uuid.UUID][]entities.Promotion, error)
+	CreatePromotion(promotion *entities.Promotion) (*entities.Promotion, error)
+	DeletePromotion(merchantId, promotionId uuid.UUID) (int64, error)
 }
 type repository struct {
 	DB *gorm.DB
@@ -37,10 +65,365 @@ func (r *repository) CreateItems(items *entities.Items) (*entities.Items, error)
 	return items, nil
 }
 
-func (r *repository) FindMerchantById(merchantId string) (*entities.Merchant, error) {
+// FindMerchantById retrieves a merchant by its ID, returning nil when it does not exist
+func (r *repository) FindMerchantById(merchantId uuid.UUID) (*entities.Merchant, error) {
 	var merchant entities.Merchant
 	if err := r.DB.Where("id = ?", merchantId).First(&merchant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
 		return nil, err
 	}
 	return &merchant, nil
 }
+
+// FindMerchants returns a page of merchants matching the given filters together with the total match count
+func (r *repository) FindMerchants(params map[string]interface{}) ([]entities.Merchant, int64, error) {
+	var merchants []entities.Merchant
+	var total int64
+
+	query := r.DB.Model(&entities.Merchant{})
+
+	if v, ok := params["ownerId"].(uuid.UUID); ok {
+		query = query.Where("owner_id = ?", v)
+	}
+
+	if v, ok := params["merchantId"].(string); ok && v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			// merchantId yang tidak valid tidak akan cocok dengan data apapun
+			return []entities.Merchant{}, 0, nil
+		}
+		query = query.Where("id = ?", id)
+	}
+
+	if v, ok := params["name"].(string); ok && v != "" {
+		query = query.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(v)+"%")
+	}
+
+	if v, ok := params["merchantCategory"].(string); ok && v != "" {
+		query = query.Where("merchant_category = ?", v)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := "created_at DESC"
+	if v, ok := params["createdAt"].(string); ok && v == "asc" {
+		order = "created_at ASC"
+	}
+
+	err := query.
+		Order(order).
+		Offset(params["offset"].(int)).
+		Limit(params["limit"].(int)).
+		Find(&merchants).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return merchants, total, nil
+}
+
+// FindItems returns a page of a merchant's items matching the given filters together with the total match count
+func (r *repository) FindItems(merchantId uuid.UUID, params map[string]interface{}) ([]entities.Items, int64, error) {
+	var items []entities.Items
+	var total int64
+
+	query := r.DB.Model(&entities.Items{}).Where("merchant_id = ?", merchantId)
+
+	if v, ok := params["itemId"].(string); ok && v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return []entities.Items{}, 0, nil
+		}
+		query = query.Where("id = ?", id)
+	}
+
+	if v, ok := params["name"].(string); ok && v != "" {
+		query = query.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(v)+"%")
+	}
+
+	if v, ok := params["productCategory"].(string); ok && v != "" {
+		query = query.Where("product_category = ?", v)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := "created_at DESC"
+	if v, ok := params["createdAt"].(string); ok && v == "asc" {
+		order = "created_at ASC"
+	}
+
+	err := query.
+		Preload("Tags", orderTags).
+		Order(order).
+		Offset(params["offset"].(int)).
+		Limit(params["limit"].(int)).
+		Find(&items).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
+
+// FindItemById retrieves an item of a merchant by its ID, returning nil when it does not exist
+func (r *repository) FindItemById(merchantId, itemId uuid.UUID) (*entities.Items, error) {
+	var items entities.Items
+	if err := r.DB.Preload("Tags", orderTags).Where("id = ? AND merchant_id = ?", itemId, merchantId).First(&items).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &items, nil
+}
+
+func (r *repository) UpdateMerchant(merchant *entities.Merchant) (*entities.Merchant, error) {
+	if err := r.DB.Save(merchant).Error; err != nil {
+		return nil, err
+	}
+	return merchant, nil
+}
+
+// UpdateItems saves an item and replaces its tags with the ones it currently holds
+func (r *repository) UpdateItems(items *entities.Items) (*entities.Items, error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Tags").Save(items).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("item_id = ?", items.ID).Delete(&entities.ItemTag{}).Error; err != nil {
+			return err
+		}
+		if len(items.Tags) == 0 {
+			return nil
+		}
+		for i := range items.Tags {
+			items.Tags[i].ItemID = items.ID
+		}
+		return tx.Create(&items.Tags).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// DeleteMerchant soft deletes a merchant by setting its deleted_at column
+func (r *repository) DeleteMerchant(merchantId uuid.UUID) error {
+	return r.DB.Delete(&entities.Merchant{}, "id = ?", merchantId).Error
+}
+
+// DeleteItems soft deletes an item by setting its deleted_at column
+func (r *repository) DeleteItems(itemId uuid.UUID) error {
+	return r.DB.Delete(&entities.Items{}, "id = ?", itemId).Error
+}
+
+// ImportMerchants inserts merchants together with their items in a single transaction
+func (r *repository) ImportMerchants(merchants []entities.Merchant) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		for i := range merchants {
+			if err := tx.Create(&merchants[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CatalogCursor walks an opened catalog query, see OpenCatalog
+type CatalogCursor interface {
+	// Each calls fn once per merchant with its items
+	Each(fn func(merchant entities.Merchant, items []entities.Items) error) error
+	Close() error
+}
+
+// OpenCatalog runs the query of all merchants with their items. Rows are read with a cursor
+// so only a single merchant is held in memory at a time, the cursor must be closed.
+func (r *repository) OpenCatalog(params map[string]interface{}) (CatalogCursor, error) {
+	query := r.DB.
+		Table("merchants m").
+		Select(`m.id, m.name, m.image_url, m.lat, m.long, m.merchant_category, m.max_delivery_km, m.min_order_value, m.created_at,
+			i.id, i.name, i.product_category, i.price, i.image_url, i.stock, i.is_available, i.created_at`).
+		Joins("LEFT JOIN items i ON i.merchant_id = m.id AND i.deleted_at IS NULL").
+		Where("m.deleted_at IS NULL")
+
+	if v, ok := params["ownerId"].(uuid.UUID); ok {
+		query = query.Where("m.owner_id = ?", v)
+	}
+
+	rows, err := query.
+		Order("m.created_at, m.id, i.created_at").
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	return &catalogCursor{rows: rows}, nil
+}
+
+type catalogCursor struct {
+	rows *sql.Rows
+}
+
+func (c *catalogCursor) Each(fn func(merchant entities.Merchant, items []entities.Items) error) error {
+	var current *entities.Merchant
+	var items []entities.Items
+
+	for c.rows.Next() {
+		var m entities.Merchant
+		var itemID uuid.NullUUID
+		var itemName, itemCategory, itemImage sql.NullString
+		var itemPrice entities.Money
+		var itemStock sql.NullInt64
+		var itemAvailable sql.NullBool
+		var itemCreatedAt sql.NullInt64
+
+		if err := c.rows.Scan(&m.ID, &m.Name, &m.ImageUrl, &m.Lat, &m.Long, &m.MerchantCategory, &m.MaxDeliveryKm, &m.MinOrderValue, &m.CreatedAt,
+			&itemID, &itemName, &itemCategory, &itemPrice, &itemImage, &itemStock, &itemAvailable, &itemCreatedAt); err != nil {
+			return err
+		}
+
+		if current == nil || current.ID != m.ID {
+			if current != nil {
+				if err := fn(*current, items); err != nil {
+					return err
+				}
+			}
+			current = &m
+			items = items[:0]
+		}
+
+		if itemID.Valid {
+			item := entities.Items{
+				ID:              itemID.UUID,
+				Name:            itemName.String,
+				ProductCategory: entities.ProductCategory(itemCategory.String),
+				Price:           itemPrice,
+				ImageUrl:        itemImage.String,
+				MerchantID:      m.ID,
+				IsAvailable:     itemAvailable.Bool,
+				CreatedAt:       itemCreatedAt.Int64,
+			}
+			if itemStock.Valid {
+				stock := int(itemStock.Int64)
+				item.Stock = &stock
+			}
+			items = append(items, item)
+		}
+	}
+	if err := c.rows.Err(); err != nil {
+		return err
+	}
+
+	if current != nil {
+		return fn(*current, items)
+	}
+	return nil
+}
+
+func (c *catalogCursor) Close() error {
+	return c.rows.Close()
+}
+
+// orderTags keeps item tags in alphabetical order when preloading
+func orderTags(db *gorm.DB) *gorm.DB {
+	return db.Order("tag")
+}
+
+// FindSchedules loads the opening hours and closures of the given merchants keyed by merchant ID
+func (r *repository) FindSchedules(merchantIds []uuid.UUID) (map[uuid.UUID][]entities.OpeningHours, map[uuid.UUID][]entities.MerchantClosure, error) {
+	return FindSchedules(r.DB, merchantIds)
+}
+
+// ReplaceOpeningHours swaps the weekly schedule of a merchant in a single transaction
+func (r *repository) ReplaceOpeningHours(merchantId uuid.UUID, timezone string, hours []entities.OpeningHours) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if timezone != "" {
+			if err := tx.Model(&entities.Merchant{}).Where("id = ?", merchantId).Update("timezone", timezone).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("merchant_id = ?", merchantId).Delete(&entities.OpeningHours{}).Error; err != nil {
+			return err
+		}
+		if len(hours) == 0 {
+			return nil
+		}
+		return tx.Create(&hours).Error
+	})
+}
+
+func (r *repository) CreateClosure(closure *entities.MerchantClosure) (*entities.MerchantClosure, error) {
+	if err := r.DB.Create(closure).Error; err != nil {
+		return nil, err
+	}
+	return closure, nil
+}
+
+// DeleteClosure removes a closure of a merchant, returning the number of deleted rows
+func (r *repository) DeleteClosure(merchantId, closureId uuid.UUID) (int64, error) {
+	result := r.DB.Where("id = ? AND merchant_id = ?", closureId, merchantId).Delete(&entities.MerchantClosure{})
+	return result.RowsAffected, result.Error
+}
+
+// FindModifierGroups loads the modifier groups with their options of the given items keyed by item ID
+func (r *repository) FindModifierGroups(itemIds []uuid.UUID) (map[uuid.UUID][]entities.ModifierGroup, error) {
+	return FindModifierGroups(r.DB, itemIds)
+}
+
+// CreateModifierGroup inserts a modifier group together with its options
+func (r *repository) CreateModifierGroup(group *entities.ModifierGroup) (*entities.ModifierGroup, error) {
+	if err := r.DB.Create(group).Error; err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// DeleteModifierGroup removes a modifier group of an item, its options are removed by the foreign key cascade
+func (r *repository) DeleteModifierGroup(itemId, groupId uuid.UUID) (int64, error) {
+	result := r.DB.Where("id = ? AND item_id = ?", groupId, itemId).Delete(&entities.ModifierGroup{})
+	return result.RowsAffected, result.Error
+}
+
+// FindPromotions lists every promotion of a merchant, newest first
+func (r *repository) FindPromotions(merchantId uuid.UUID) ([]entities.Promotion, error) {
+	var promotions []entities.Promotion
+	err := r.DB.
+		Preload("Items").
+		Where("merchant_id = ?", merchantId).
+		Order("created_at DESC").
+		Find(&promotions).Error
+	if err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+// FindActivePromotions loads the promotions of the given merchants whose date range contains t,
+// keyed by merchant ID. Daily windows are left to Promotion.ActiveAt.
+func (r *repository) FindActivePromotions(merchantIds []uuid.UUID, t time.Time) (map[uuid.UUID][]entities.Promotion, error) {
+	return FindActivePromotions(r.DB, merchantIds, t)
+}
+
+// CreatePromotion inserts a promotion together with its items
+func (r *repository) CreatePromotion(promotion *entities.Promotion) (*entities.Promotion, error) {
+	if err := r.DB.Create(promotion).Error; err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+// DeletePromotion removes a promotion of a merchant, returning the number of deleted rows
+func (r *repository) DeletePromotion(merchantId, promotionId uuid.UUID) (int64, error) {
+	result := r.DB.Where("id = ? AND merchant_id = ?", promotionId, merchantId).Delete(&entities.Promotion{})
+	return result.RowsAffected, result.Error
+}
+
+// FindRatings aggregates the visible reviews of the given merchants keyed by merchant ID
+func (r *repository) FindRatings(merchantIds []uuid.UUID) (map[uuid.UUID]dtos.RatingSummary, error) {
+	return FindRatings(r.DB, merchantIds)
+}
@@ -0,0 +1,46 @@
+package merchant
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunRepo builds the queries of the repository without a database, so relations
+// GORM cannot resolve still fail the way they do against Postgres
+func dryRunRepo(t *testing.T) *repository {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=belimang"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &repository{DB: db}
+}
+
+func TestFindMerchantsQuery(t *testing.T) {
+	params := map[string]interface{}{
+		"name":             "kopi",
+		"merchantCategory": "SmallRestaurant",
+		"createdAt":        "asc",
+		"offset":           0,
+		"limit":            5,
+	}
+	if _, _, err := dryRunRepo(t).FindMerchants(params); err != nil {
+		t.Fatalf("GET /admin/merchants query fails: %v", err)
+	}
+}
+
+func TestFindItemsQuery(t *testing.T) {
+	params := map[string]interface{}{
+		"offset": 0,
+		"limit":  5,
+	}
+	if _, _, err := dryRunRepo(t).FindItems(uuid.Nil, params); err != nil {
+		t.Fatalf("GET /admin/merchants/:merchantId/items query fails: %v", err)
+	}
+}
@@ -1,39 +1,653 @@
 package merchant
 
 import (
+	"belimang/src/pkg/category"
+	"belimang/src/pkg/dtos"
 	"belimang/src/pkg/entities"
+	"errors"
+	"io"
+	"sort"
+	"time"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
+var (
+	ErrMerchantNotFound = errors.New("merchant not found")
+	ErrItemNotFound     = errors.New("item not found")
+	ErrClosureNotFound  = errors.New("closure not found")
+	ErrInvalidClosure   = errors.New("closure needs YYYY-MM-DD dates with endDate not before startDate")
+
+	ErrInvalidOpeningHours = errors.New("opening hours need a dayOfWeek from 0 to 6 and HH:MM open and close times")
+
+	ErrModifierGroupNotFound = errors.New("modifier group not found")
+	ErrInvalidModifierGroup  = errors.New("modifier group must satisfy minSelect <= maxSelect <= number of options")
+
+	ErrPromotionNotFound = errors.New("promotion not found")
+	ErrInvalidPromotion  = errors.New("promotion needs RFC3339 dates with endsAt after startsAt, HH:MM daily hours, valid item ids and a discount matching discountType")
+)
+
+// Actor is the admin performing a merchant operation
+type Actor struct {
+	UserID     uuid.UUID
+	SuperAdmin bool
+}
+
+// CanManage reports whether the actor owns the merchant, superadmins can manage every merchant
+func (a Actor) CanManage(merchant *entities.Merchant) bool {
+	if a.SuperAdmin {
+		return true
+	}
+	return merchant.OwnerID != nil && *merchant.OwnerID == a.UserID
+}
+
+// Scope restricts repository queries to the merchants owned by the actor through params["ownerId"]
+func (a Actor) Scope(params map[string]interface{}) map[string]interface{} {
+	if !a.SuperAdmin {
+		params["ownerId"] = a.UserID
+	}
+	return params
+}
+
 // Service is an interface from which our api module can access our repository of all our models
 type Service interface {
-	InsertMerchant(merchant *entities.Merchant) (*entities.Merchant, error)
-	CreateItems(items *entities.Items, merchantId uuid.UUID) (*entities.Items, error)
+	InsertMerchant(actor Actor, merchant *entities.Merchant) (*entities.Merchant, error)
+	CreateItems(actor Actor, items *entities.Items, merchantId uuid.UUID) (*entities.Items, error)
+	FetchMerchants(actor Actor, params map[string]interface{}) (*dtos.MerchantListResponse, error)
+	FetchItems(actor Actor, merchantId uuid.UUID, params map[string]interface{}) (*dtos.ItemListResponse, error)
+	UpdateMerchant(actor Actor, merchantId uuid.UUID, req entities.UpdateMerchantRequest) (*entities.Merchant, error)
+	UpdateItems(actor Actor, merchantId, itemId uuid.UUID, req entities.UpdateItemsRequest) (*entities.Items, error)
+	RemoveMerchant(actor Actor, merchantId uuid.UUID) error
+	RemoveItems(actor Actor, merchantId, itemId uuid.UUID) error
+	ImportMerchants(actor Actor, file io.Reader, format string, dryRun bool) (*ImportResult, error)
+	ExportCatalog(actor Actor, format string) (*CatalogExport, error)
+	FetchOpeningHours(actor Actor, merchantId uuid.UUID) (*dtos.OpeningHoursResponse, error)
+	UpdateOpeningHours(actor Actor, merchantId uuid.UUID, req entities.RequestOpeningHours) (*dtos.OpeningHoursResponse, error)
+	AddClosure(actor Actor, merchantId uuid.UUID, req entities.RequestClosure) (*dtos.ClosureResponse, error)
+	RemoveClosure(actor Actor, merchantId, closureId uuid.UUID) error
+	FetchModifierGroups(actor Actor, merchantId, itemId uuid.UUID) ([]dtos.ModifierGroupResponse, error)
+	AddModifierGroup(actor Actor, merchantId, itemId uuid.UUID, req entities.RequestModifierGroup) (*dtos.ModifierGroupResponse, error)
+	RemoveModifierGroup(actor Actor, merchantId, itemId, groupId uuid.UUID) error
+	FetchPromotions(actor Actor, merchantId uuid.UUID) ([]dtos.PromotionResponse, error)
+	AddPromotion(actor Actor, merchantId uuid.UUID, req entities.RequestPromotion) (*dtos.PromotionResponse, error)
+	RemovePromotion(actor Actor, merchantId, promotionId uuid.UUID) error
+	Validate(req interface{}) error
 }
 
 type service struct {
 	repository Repository
+	validate   *validator.Validate
 }
 
 // NewService is used to create a single instance of the service
-func NewService(r Repository) Service {
+func NewService(r Repository, categories category.Service) Service {
 	return &service{
 		repository: r,
+		validate:   newValidator(categories),
 	}
 }
 
+// Validate checks a merchant or item request, including that its category exists
+func (s *service) Validate(req interface{}) error {
+	return category.Validate(s.validate, req)
+}
+
 // InsertBook is a service layer that helps insert book in BookShop
-func (s *service) InsertMerchant(merchant *entities.Merchant) (*entities.Merchant, error) {
+func (s *service) InsertMerchant(actor Actor, merchant *entities.Merchant) (*entities.Merchant, error) {
+	merchant.OwnerID = &actor.UserID
 	return s.repository.CreateMerchant(merchant)
 }
 
-func (s *service) CreateItems(items *entities.Items, merchantId uuid.UUID) (*entities.Items, error) {
+func (s *service) CreateItems(actor Actor, items *entities.Items, merchantId uuid.UUID) (*entities.Items, error) {
 	//cek apakah merchantId ada di db
+	if _, err := s.findMerchant(actor, merchantId); err != nil {
+		return nil, err
+	}
 
+	items.MerchantID = merchantId
 	return s.repository.CreateItems(items)
 }
 
+// FetchMerchants is a service layer that helps list merchants for the admin
+func (s *service) FetchMerchants(actor Actor, params map[string]interface{}) (*dtos.MerchantListResponse, error) {
+	merchants, total, err := s.repository.FindMerchants(actor.Scope(params))
+	if err != nil {
+		return nil, err
+	}
+
+	merchantIds := make([]uuid.UUID, len(merchants))
+	for i, m := range merchants {
+		merchantIds[i] = m.ID
+	}
+	hours, closures, err := s.repository.FindSchedules(merchantIds)
+	if err != nil {
+		return nil, err
+	}
+	ratings, err := s.repository.FindRatings(merchantIds)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	data := make([]dtos.MerchantResponse, len(merchants))
+	for i, m := range merchants {
+		isOpen := entities.IsOpenAt(m.Timezone, hours[m.ID], closures[m.ID], now)
+		rating := ratings[m.ID]
+		data[i] = dtos.ToMerchantResponse(m)
+		data[i].IsOpen = &isOpen
+		data[i].Rating = &rating
+	}
+
+	return &dtos.MerchantListResponse{
+		Data: data,
+		Meta: dtos.MetaResponse{
+			Limit:  params["limit"].(int),
+			Offset: params["offset"].(int),
+			Total:  int(total),
+		},
+	}, nil
+}
+
+// FetchItems is a service layer that helps list the items of a merchant
+func (s *service) FetchItems(actor Actor, merchantId uuid.UUID, params map[string]interface{}) (*dtos.ItemListResponse, error) {
+	merchant, err := s.findMerchant(actor, merchantId)
+	if err != nil {
+		return nil, err
+	}
+
+	items, total, err := s.repository.FindItems(merchantId, params)
+	if err != nil {
+		return nil, err
+	}
+
+	itemIds := make([]uuid.UUID, len(items))
+	for i, item := range items {
+		itemIds[i] = item.ID
+	}
+	modifiers, err := s.repository.FindModifierGroups(itemIds)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	promotions, err := s.repository.FindActivePromotions([]uuid.UUID{merchantId}, now)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]dtos.ItemResponse, len(items))
+	for i, item := range items {
+		data[i] = dtos.ToItemResponse(item)
+		data[i].Modifiers = dtos.ToModifierGroupResponses(modifiers[item.ID])
+		data[i].ApplyPromotions(item, merchant.Timezone, promotions[merchantId], now)
+	}
+
+	return &dtos.ItemListResponse{
+		Data: data,
+		Meta: dtos.MetaResponse{
+			Limit:  params["limit"].(int),
+			Offset: params["offset"].(int),
+			Total:  int(total),
+		},
+	}, nil
+}
+
+// UpdateMerchant is a service layer that helps update the provided fields of a merchant
+func (s *service) UpdateMerchant(actor Actor, merchantId uuid.UUID, req entities.UpdateMerchantRequest) (*entities.Merchant, error) {
+	merchant, err := s.findMerchant(actor, merchantId)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		merchant.Name = *req.Name
+	}
+	if req.ImageUrl != nil {
+		merchant.ImageUrl = *req.ImageUrl
+	}
+	if req.Location != nil {
+		merchant.Lat = req.Location.Lat
+		merchant.Long = req.Location.Long
+	}
+	if req.MerchantCategory != nil {
+		merchant.MerchantCategory = *req.MerchantCategory
+	}
+	if req.MaxDeliveryKm != nil {
+		merchant.MaxDeliveryKm = *req.MaxDeliveryKm
+	}
+	if req.MinOrderValue != nil {
+		merchant.MinOrderValue = *req.MinOrderValue
+	}
+
+	return s.repository.UpdateMerchant(merchant)
+}
+
+// UpdateItems is a service layer that helps update the provided fields of a merchant's item
+func (s *service) UpdateItems(actor Actor, merchantId, itemId uuid.UUID, req entities.UpdateItemsRequest) (*entities.Items, error) {
+	items, err := s.findItems(actor, merchantId, itemId)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		items.Name = *req.Name
+	}
+	if req.ProductCategory != nil {
+		items.ProductCategory = *req.ProductCategory
+	}
+	if req.Price != nil {
+		items.Price = *req.Price
+	}
+	if req.ImageUrl != nil {
+		items.ImageUrl = *req.ImageUrl
+	}
+	if req.Stock != nil {
+		items.Stock = req.Stock
+	}
+	if req.IsAvailable != nil {
+		items.IsAvailable = *req.IsAvailable
+	}
+	if req.Tags != nil {
+		items.Tags = entities.ToItemTags(*req.Tags)
+	}
+
+	return s.repository.UpdateItems(items)
+}
+
+// RemoveMerchant is a service layer that helps soft delete a merchant
+func (s *service) RemoveMerchant(actor Actor, merchantId uuid.UUID) error {
+	if _, err := s.findMerchant(actor, merchantId); err != nil {
+		return err
+	}
+	return s.repository.DeleteMerchant(merchantId)
+}
+
+// RemoveItems is a service layer that helps soft delete a merchant's item
+func (s *service) RemoveItems(actor Actor, merchantId, itemId uuid.UUID) error {
+	if _, err := s.findItems(actor, merchantId, itemId); err != nil {
+		return err
+	}
+	return s.repository.DeleteItems(itemId)
+}
+
+// ImportMerchants is a service layer that helps bulk insert merchants with their items.
+// Nothing is written when any row fails validation or when dryRun is set.
+func (s *service) ImportMerchants(actor Actor, file io.Reader, format string, dryRun bool) (*ImportResult, error) {
+	parsed, rowErrors, err := parseImport(file, format)
+	if err != nil {
+		return nil, err
+	}
+	invalid, err := validateImport(s.validate, parsed)
+	if err != nil {
+		return nil, err
+	}
+	rowErrors = append(rowErrors, invalid...)
+	sort.SliceStable(rowErrors, func(i, j int) bool {
+		return rowErrors[i].Row < rowErrors[j].Row
+	})
+
+	result := &ImportResult{
+		DryRun:    dryRun,
+		Merchants: len(parsed),
+		Errors:    rowErrors,
+	}
+	for _, m := range parsed {
+		result.Items += len(m.items)
+	}
+
+	if len(rowErrors) > 0 || dryRun {
+		return result, nil
+	}
+
+	merchants := make([]entities.Merchant, len(parsed))
+	for i, m := range parsed {
+		merchants[i] = entities.Merchant{
+			Name:             m.data.Name,
+			ImageUrl:         m.data.ImageUrl,
+			Lat:              m.data.Location.Lat,
+			Long:             m.data.Location.Long,
+			MerchantCategory: m.data.MerchantCategory,
+			OwnerID:          &actor.UserID,
+		}
+		if m.data.MaxDeliveryKm != nil {
+			merchants[i].MaxDeliveryKm = *m.data.MaxDeliveryKm
+		}
+		if m.data.MinOrderValue != nil {
+			merchants[i].MinOrderValue = *m.data.MinOrderValue
+		}
+		for _, item := range m.items {
+			merchants[i].Items = append(merchants[i].Items, entities.Items{
+				Name:            item.data.Name,
+				ProductCategory: item.data.ProductCategory,
+				Price:           item.data.Price,
+				ImageUrl:        item.data.ImageUrl,
+				IsAvailable:     true,
+			})
+		}
+	}
+
+	if err := s.repository.ImportMerchants(merchants); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ExportCatalog is a service layer that helps export every merchant with its items in the given format.
+// The format is checked and the query started here, the caller streams and closes the export.
+func (s *service) ExportCatalog(actor Actor, format string) (*CatalogExport, error) {
+	if _, err := ExportContentType(format); err != nil {
+		return nil, err
+	}
+
+	cursor, err := s.repository.OpenCatalog(actor.Scope(map[string]interface{}{}))
+	if err != nil {
+		return nil, err
+	}
+	return &CatalogExport{format: format, cursor: cursor}, nil
+}
+
+// FetchOpeningHours is a service layer that helps fetch the weekly schedule and closures of a merchant
+func (s *service) FetchOpeningHours(actor Actor, merchantId uuid.UUID) (*dtos.OpeningHoursResponse, error) {
+	merchant, err := s.findMerchant(actor, merchantId)
+	if err != nil {
+		return nil, err
+	}
+	return s.openingHours(merchant)
+}
+
+// UpdateOpeningHours is a service layer that helps replace the weekly schedule of a merchant
+func (s *service) UpdateOpeningHours(actor Actor, merchantId uuid.UUID, req entities.RequestOpeningHours) (*dtos.OpeningHoursResponse, error) {
+	merchant, err := s.findMerchant(actor, merchantId)
+	if err != nil {
+		return nil, err
+	}
+
+	hours := make([]entities.OpeningHours, len(req.Hours))
+	for i, h := range req.Hours {
+		if h.DayOfWeek == nil || *h.DayOfWeek < 0 || *h.DayOfWeek > 6 {
+			return nil, ErrInvalidOpeningHours
+		}
+		open, err := entities.ParseClock(h.Open)
+		if err != nil {
+			return nil, ErrInvalidOpeningHours
+		}
+		closeAt, err := entities.ParseClock(h.Close)
+		if err != nil {
+			return nil, ErrInvalidOpeningHours
+		}
+		hours[i] = entities.OpeningHours{
+			MerchantID:  merchantId,
+			DayOfWeek:   *h.DayOfWeek,
+			OpenMinute:  open,
+			CloseMinute: closeAt,
+		}
+	}
+
+	if err := s.repository.ReplaceOpeningHours(merchantId, req.Timezone, hours); err != nil {
+		return nil, err
+	}
+	if req.Timezone != "" {
+		merchant.Timezone = req.Timezone
+	}
+	return s.openingHours(merchant)
+}
+
+// AddClosure is a service layer that helps close a merchant for a range of dates
+func (s *service) AddClosure(actor Actor, merchantId uuid.UUID, req entities.RequestClosure) (*dtos.ClosureResponse, error) {
+	if _, err := s.findMerchant(actor, merchantId); err != nil {
+		return nil, err
+	}
+
+	start, err := time.Parse(time.DateOnly, req.StartDate)
+	if err != nil {
+		return nil, ErrInvalidClosure
+	}
+	end, err := time.Parse(time.DateOnly, req.EndDate)
+	if err != nil {
+		return nil, ErrInvalidClosure
+	}
+	if end.Before(start) {
+		return nil, ErrInvalidClosure
+	}
+
+	closure, err := s.repository.CreateClosure(&entities.MerchantClosure{
+		MerchantID: merchantId,
+		StartDate:  start,
+		EndDate:    end,
+		Reason:     req.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := dtos.ToOpeningHoursResponse("", nil, []entities.MerchantClosure{*closure}).Closures[0]
+	return &res, nil
+}
+
+// RemoveClosure is a service layer that helps delete a closure of a merchant
+func (s *service) RemoveClosure(actor Actor, merchantId, closureId uuid.UUID) error {
+	if _, err := s.findMerchant(actor, merchantId); err != nil {
+		return err
+	}
+
+	deleted, err := s.repository.DeleteClosure(merchantId, closureId)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrClosureNotFound
+	}
+	return nil
+}
+
+// FetchModifierGroups is a service layer that helps list the modifier groups of an item
+func (s *service) FetchModifierGroups(actor Actor, merchantId, itemId uuid.UUID) ([]dtos.ModifierGroupResponse, error) {
+	if _, err := s.findItems(actor, merchantId, itemId); err != nil {
+		return nil, err
+	}
+
+	groups, err := s.repository.FindModifierGroups([]uuid.UUID{itemId})
+	if err != nil {
+		return nil, err
+	}
+	return dtos.ToModifierGroupResponses(groups[itemId]), nil
+}
+
+// AddModifierGroup is a service layer that helps add a modifier group with its options to an item
+func (s *service) AddModifierGroup(actor Actor, merchantId, itemId uuid.UUID, req entities.RequestModifierGroup) (*dtos.ModifierGroupResponse, error) {
+	if _, err := s.findItems(actor, merchantId, itemId); err != nil {
+		return nil, err
+	}
+
+	// grup wajib minimal memilih satu opsi
+	minSelect := req.MinSelect
+	if req.IsRequired && minSelect == 0 {
+		minSelect = 1
+	}
+	if minSelect > req.MaxSelect || req.MaxSelect > len(req.Options) {
+		return nil, ErrInvalidModifierGroup
+	}
+
+	group := entities.ModifierGroup{
+		ItemID:     itemId,
+		Name:       req.Name,
+		IsRequired: minSelect > 0,
+		MinSelect:  minSelect,
+		MaxSelect:  req.MaxSelect,
+		Options:    make([]entities.ModifierOption, len(req.Options)),
+	}
+	for i, o := range req.Options {
+		group.Options[i] = entities.ModifierOption{
+			Name:       o.Name,
+			PriceDelta: o.PriceDelta,
+		}
+	}
+
+	created, err := s.repository.CreateModifierGroup(&group)
+	if err != nil {
+		return nil, err
+	}
+	res := dtos.ToModifierGroupResponses([]entities.ModifierGroup{*created})[0]
+	return &res, nil
+}
+
+// RemoveModifierGroup is a service layer that helps delete a modifier group of an item
+func (s *service) RemoveModifierGroup(actor Actor, merchantId, itemId, groupId uuid.UUID) error {
+	if _, err := s.findItems(actor, merchantId, itemId); err != nil {
+		return err
+	}
+
+	deleted, err := s.repository.DeleteModifierGroup(itemId, groupId)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrModifierGroupNotFound
+	}
+	return nil
+}
+
+// FetchPromotions is a service layer that helps list the promotions of a merchant
+func (s *service) FetchPromotions(actor Actor, merchantId uuid.UUID) ([]dtos.PromotionResponse, error) {
+	if _, err := s.findMerchant(actor, merchantId); err != nil {
+		return nil, err
+	}
+
+	promotions, err := s.repository.FindPromotions(merchantId)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dtos.PromotionResponse, len(promotions))
+	for i, p := range promotions {
+		res[i] = dtos.ToPromotionResponse(p)
+	}
+	return res, nil
+}
+
+// AddPromotion is a service layer that helps schedule a promotion for a merchant
+func (s *service) AddPromotion(actor Actor, merchantId uuid.UUID, req entities.RequestPromotion) (*dtos.PromotionResponse, error) {
+	if _, err := s.findMerchant(actor, merchantId); err != nil {
+		return nil, err
+	}
+
+	startsAt, err := time.Parse(time.RFC3339, req.StartsAt)
+	if err != nil {
+		return nil, ErrInvalidPromotion
+	}
+	endsAt, err := time.Parse(time.RFC3339, req.EndsAt)
+	if err != nil {
+		return nil, ErrInvalidPromotion
+	}
+	if !endsAt.After(startsAt) || !entities.ValidDiscount(req.DiscountType, req.DiscountPercent, req.DiscountAmount) {
+		return nil, ErrInvalidPromotion
+	}
+
+	promotion := entities.Promotion{
+		MerchantID:      merchantId,
+		Name:            req.Name,
+		Scope:           req.Scope,
+		DiscountType:    req.DiscountType,
+		DiscountPercent: req.DiscountPercent,
+		DiscountAmount:  req.DiscountAmount,
+		StartsAt:        startsAt,
+		EndsAt:          endsAt,
+	}
+	switch req.Scope {
+	case entities.PromotionScopeCategory:
+		promotion.ProductCategory = req.ProductCategory
+	case entities.PromotionScopeItems:
+		seen := map[uuid.UUID]bool{}
+		for _, v := range req.ItemIDs {
+			itemId, err := uuid.Parse(v)
+			if err != nil {
+				return nil, ErrInvalidPromotion
+			}
+			if seen[itemId] {
+				continue
+			}
+			seen[itemId] = true
+			// item harus milik merchant yang sama
+			if _, err := s.findItems(actor, merchantId, itemId); err != nil {
+				return nil, err
+			}
+			promotion.Items = append(promotion.Items, entities.PromotionItem{ItemID: itemId})
+		}
+	}
+	if req.DailyStart != "" || req.DailyEnd != "" {
+		start, err := entities.ParseClock(req.DailyStart)
+		if err != nil {
+			return nil, ErrInvalidPromotion
+		}
+		end, err := entities.ParseClock(req.DailyEnd)
+		if err != nil {
+			return nil, ErrInvalidPromotion
+		}
+		promotion.DailyStartMinute = &start
+		promotion.DailyEndMinute = &end
+	}
+
+	created, err := s.repository.CreatePromotion(&promotion)
+	if err != nil {
+		return nil, err
+	}
+	res := dtos.ToPromotionResponse(*created)
+	return &res, nil
+}
+
+// RemovePromotion is a service layer that helps delete a promotion of a merchant
+func (s *service) RemovePromotion(actor Actor, merchantId, promotionId uuid.UUID) error {
+	if _, err := s.findMerchant(actor, merchantId); err != nil {
+		return err
+	}
+
+	deleted, err := s.repository.DeletePromotion(merchantId, promotionId)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrPromotionNotFound
+	}
+	return nil
+}
+
+func (s *service) openingHours(merchant *entities.Merchant) (*dtos.OpeningHoursResponse, error) {
+	hours, closures, err := s.repository.FindSchedules([]uuid.UUID{merchant.ID})
+	if err != nil {
+		return nil, err
+	}
+	res := dtos.ToOpeningHoursResponse(merchant.Timezone, hours[merchant.ID], closures[merchant.ID])
+	return &res, nil
+}
+
+// findMerchant looks up a merchant managed by the actor. Merchants owned by another
+// admin are reported as not found so their existence is not leaked.
+func (s *service) findMerchant(actor Actor, merchantId uuid.UUID) (*entities.Merchant, error) {
+	merchant, err := s.repository.FindMerchantById(merchantId)
+	if err != nil {
+		return nil, err
+	}
+	if merchant == nil || !actor.CanManage(merchant) {
+		return nil, ErrMerchantNotFound
+	}
+	return merchant, nil
+}
+
+// findItems looks up an item making sure both the merchant and the item still exist
+func (s *service) findItems(actor Actor, merchantId, itemId uuid.UUID) (*entities.Items, error) {
+	if _, err := s.findMerchant(actor, merchantId); err != nil {
+		return nil, err
+	}
+
+	items, err := s.repository.FindItemById(merchantId, itemId)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		return nil, ErrItemNotFound
+	}
+	return items, nil
+}
+
 // FetchBooks is a service layer that helps fetch all books in BookShop
 // func (s *service) FetchBooks() (*[]presenter.Book, error) {
 // 	return s.repository.ReadBook()
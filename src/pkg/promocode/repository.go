@@ -0,0 +1,142 @@
+package promocode
+
+import (
+	"belimang/src/pkg/entities"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Repository interface allows us to access the CRUD Operations here.
+type Repository interface {
+	CreatePromoCode(promoCode *entities.PromoCode) (*entities.PromoCode, error)
+	FindPromoCodes(params map[string]interface{}) ([]entities.PromoCode, int64, error)
+	FindPromoCodeById(promoCodeId uuid.UUID, params map[string]interface{}) (*entities.PromoCode, error)
+	FindPromoCodeByCode(code string) (*entities.PromoCode, error)
+	UpdatePromoCode(promoCode *entities.PromoCode) (*entities.PromoCode, error)
+	CountOwnedMerchants(ownerId uuid.UUID, merchantIds []uuid.UUID) (int64, error)
+	CountRedemptions(promoCodeIds []uuid.UUID) (map[uuid.UUID]int64, error)
+}
+type repository struct {
+	DB *gorm.DB
+}
+
+// NewRepo is the single instance repo that is being created.
+func NewRepo(db *gorm.DB) Repository {
+	return &repository{
+		DB: db,
+	}
+}
+
+// CreatePromoCode inserts a promo code together with its allowed merchants
+func (r *repository) CreatePromoCode(promoCode *entities.PromoCode) (*entities.PromoCode, error) {
+	if err := r.DB.Create(promoCode).Error; err != nil {
+		return nil, err
+	}
+	return promoCode, nil
+}
+
+// FindPromoCodes returns a page of promo codes, newest first, together with the total match count
+func (r *repository) FindPromoCodes(params map[string]interface{}) ([]entities.PromoCode, int64, error) {
+	var promoCodes []entities.PromoCode
+	var total int64
+
+	query := r.scoped(params)
+
+	if v, ok := params["isActive"].(bool); ok {
+		query = query.Where("is_active = ?", v)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Merchants").
+		Order("created_at DESC").
+		Offset(params["offset"].(int)).
+		Limit(params["limit"].(int)).
+		Find(&promoCodes).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return promoCodes, total, nil
+}
+
+// FindPromoCodeById retrieves a promo code by its ID, returning nil when it does not exist
+func (r *repository) FindPromoCodeById(promoCodeId uuid.UUID, params map[string]interface{}) (*entities.PromoCode, error) {
+	var promoCode entities.PromoCode
+	if err := r.scoped(params).Preload("Merchants").Where("id = ?", promoCodeId).First(&promoCode).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &promoCode, nil
+}
+
+// FindPromoCodeByCode retrieves a promo code by its code, returning nil when it does not exist
+func (r *repository) FindPromoCodeByCode(code string) (*entities.PromoCode, error) {
+	var promoCode entities.PromoCode
+	if err := r.DB.Where("code = ?", code).First(&promoCode).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &promoCode, nil
+}
+
+func (r *repository) UpdatePromoCode(promoCode *entities.PromoCode) (*entities.PromoCode, error) {
+	if err := r.DB.Omit("Merchants").Save(promoCode).Error; err != nil {
+		return nil, err
+	}
+	return promoCode, nil
+}
+
+// CountOwnedMerchants counts how many of the given merchants belong to the owner
+func (r *repository) CountOwnedMerchants(ownerId uuid.UUID, merchantIds []uuid.UUID) (int64, error) {
+	var count int64
+	err := r.DB.Model(&entities.Merchant{}).
+		Where("id IN ? AND owner_id = ?", merchantIds, ownerId).
+		Count(&count).Error
+	return count, err
+}
+
+// CountRedemptions counts how often each of the given promo codes has been used
+func (r *repository) CountRedemptions(promoCodeIds []uuid.UUID) (map[uuid.UUID]int64, error) {
+	counts := map[uuid.UUID]int64{}
+	if len(promoCodeIds) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		PromoCodeID uuid.UUID
+		Count       int64
+	}
+	err := r.DB.
+		Table("promo_code_redemptions").
+		Select("promo_code_id, COUNT(*) AS count").
+		Where("promo_code_id IN ?", promoCodeIds).
+		Group("promo_code_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.PromoCodeID] = row.Count
+	}
+	return counts, nil
+}
+
+// scoped restricts the promo codes to the ones created by params["createdBy"] when it is set
+func (r *repository) scoped(params map[string]interface{}) *gorm.DB {
+	query := r.DB.Model(&entities.PromoCode{})
+	if v, ok := params["createdBy"].(uuid.UUID); ok {
+		query = query.Where("created_by = ?", v)
+	}
+	return query
+}
@@ -0,0 +1,174 @@
+package promocode
+
+import (
+	"belimang/src/pkg/dtos"
+	"belimang/src/pkg/entities"
+	"belimang/src/pkg/merchant"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPromoCodeNotFound = errors.New("promo code not found")
+	ErrPromoCodeExists   = errors.New("promo code already exists")
+	ErrInvalidPromoCode  = errors.New("promo code needs RFC3339 dates with endsAt after startsAt, valid merchant ids and a discount matching discountType")
+	ErrMerchantsRequired = errors.New("merchantIds must list merchants you manage")
+)
+
+// Service is an interface from which our api module can access our repository of all our models
+type Service interface {
+	CreatePromoCode(actor merchant.Actor, req entities.RequestPromoCode) (*dtos.PromoCodeResponse, error)
+	FetchPromoCodes(actor merchant.Actor, params map[string]interface{}) (*dtos.PromoCodeListResponse, error)
+	DeactivatePromoCode(actor merchant.Actor, promoCodeId uuid.UUID) (*dtos.PromoCodeResponse, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+// NewService is used to create a single instance of the service
+func NewService(r Repository) Service {
+	return &service{
+		repository: r,
+	}
+}
+
+// CreatePromoCode is a service layer that helps create a promo code.
+// Admins may only create codes restricted to their own merchants, superadmins may create global codes.
+func (s *service) CreatePromoCode(actor merchant.Actor, req entities.RequestPromoCode) (*dtos.PromoCodeResponse, error) {
+	startsAt, err := time.Parse(time.RFC3339, req.StartsAt)
+	if err != nil {
+		return nil, ErrInvalidPromoCode
+	}
+	endsAt, err := time.Parse(time.RFC3339, req.EndsAt)
+	if err != nil {
+		return nil, ErrInvalidPromoCode
+	}
+	if !endsAt.After(startsAt) || !entities.ValidDiscount(req.DiscountType, req.DiscountPercent, req.DiscountAmount) {
+		return nil, ErrInvalidPromoCode
+	}
+
+	merchantIds := []uuid.UUID{}
+	seen := map[uuid.UUID]bool{}
+	for _, v := range req.MerchantIDs {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, ErrInvalidPromoCode
+		}
+		if !seen[id] {
+			seen[id] = true
+			merchantIds = append(merchantIds, id)
+		}
+	}
+	if !actor.SuperAdmin {
+		if len(merchantIds) == 0 {
+			return nil, ErrMerchantsRequired
+		}
+		owned, err := s.repository.CountOwnedMerchants(actor.UserID, merchantIds)
+		if err != nil {
+			return nil, err
+		}
+		if owned != int64(len(merchantIds)) {
+			return nil, ErrMerchantsRequired
+		}
+	}
+
+	code := entities.NormalizePromoCode(req.Code)
+	existing, err := s.repository.FindPromoCodeByCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrPromoCodeExists
+	}
+
+	promoCode := entities.PromoCode{
+		Code:            code,
+		DiscountType:    req.DiscountType,
+		DiscountPercent: req.DiscountPercent,
+		DiscountAmount:  req.DiscountAmount,
+		MinSpend:        req.MinSpend,
+		MaxDiscount:     req.MaxDiscount,
+		UsageLimit:      req.UsageLimit,
+		PerUserLimit:    req.PerUserLimit,
+		StartsAt:        startsAt,
+		EndsAt:          endsAt,
+		IsActive:        true,
+		CreatedBy:       actor.UserID,
+	}
+	for _, id := range merchantIds {
+		promoCode.Merchants = append(promoCode.Merchants, entities.PromoCodeMerchant{MerchantID: id})
+	}
+
+	created, err := s.repository.CreatePromoCode(&promoCode)
+	if err != nil {
+		return nil, err
+	}
+	res := dtos.ToPromoCodeResponse(*created, 0)
+	return &res, nil
+}
+
+// FetchPromoCodes is a service layer that helps list the promo codes managed by the actor
+func (s *service) FetchPromoCodes(actor merchant.Actor, params map[string]interface{}) (*dtos.PromoCodeListResponse, error) {
+	promoCodes, total, err := s.repository.FindPromoCodes(scope(actor, params))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(promoCodes))
+	for i, p := range promoCodes {
+		ids[i] = p.ID
+	}
+	used, err := s.repository.CountRedemptions(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]dtos.PromoCodeResponse, len(promoCodes))
+	for i, p := range promoCodes {
+		data[i] = dtos.ToPromoCodeResponse(p, used[p.ID])
+	}
+
+	return &dtos.PromoCodeListResponse{
+		Data: data,
+		Meta: dtos.MetaResponse{
+			Limit:  params["limit"].(int),
+			Offset: params["offset"].(int),
+			Total:  int(total),
+		},
+	}, nil
+}
+
+// DeactivatePromoCode is a service layer that helps stop a promo code from being used
+func (s *service) DeactivatePromoCode(actor merchant.Actor, promoCodeId uuid.UUID) (*dtos.PromoCodeResponse, error) {
+	promoCode, err := s.repository.FindPromoCodeById(promoCodeId, scope(actor, map[string]interface{}{}))
+	if err != nil {
+		return nil, err
+	}
+	if promoCode == nil {
+		return nil, ErrPromoCodeNotFound
+	}
+
+	promoCode.IsActive = false
+	promoCode, err = s.repository.UpdatePromoCode(promoCode)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := s.repository.CountRedemptions([]uuid.UUID{promoCode.ID})
+	if err != nil {
+		return nil, err
+	}
+	res := dtos.ToPromoCodeResponse(*promoCode, used[promoCode.ID])
+	return &res, nil
+}
+
+// scope restricts admins to the promo codes they created, superadmins see every code
+func scope(actor merchant.Actor, params map[string]interface{}) map[string]interface{} {
+	if !actor.SuperAdmin {
+		params["createdBy"] = actor.UserID
+	}
+	return params
+}
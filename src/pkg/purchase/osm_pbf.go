@@ -0,0 +1,404 @@
+package purchase
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Batas ukuran dari spesifikasi format PBF
+const (
+	maxBlobHeaderSize = 64 * 1024
+	maxBlobSize       = 32 * 1024 * 1024
+)
+
+var ErrInvalidPBF = errors.New("invalid OSM PBF file")
+
+// protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// decodeOSMPBF reads an OSM PBF extract. Only uncompressed and zlib blobs are supported,
+// which is what osmium and the Geofabrik extracts produce.
+func decodeOSMPBF(r io.Reader, onWay func(refs []int64, tags map[string]string), onNode func(id int64, lat, long float64)) error {
+	var size [4]byte
+	var zr io.ReadCloser
+	for {
+		if _, err := io.ReadFull(r, size[:]); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPBF, err)
+		}
+
+		headerSize := binary.BigEndian.Uint32(size[:])
+		if headerSize > maxBlobHeaderSize {
+			return fmt.Errorf("%w: blob header of %d bytes", ErrInvalidPBF, headerSize)
+		}
+		header := make([]byte, headerSize)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPBF, err)
+		}
+
+		var blobType string
+		var blobSize uint64
+		p := pbMessage{buf: header}
+		for p.next() {
+			switch p.field {
+			case 1:
+				blobType = string(p.bytes())
+			case 3:
+				blobSize = p.varint()
+			default:
+				p.skip()
+			}
+		}
+		if p.err != nil {
+			return p.err
+		}
+		if blobSize > maxBlobSize {
+			return fmt.Errorf("%w: blob of %d bytes", ErrInvalidPBF, blobSize)
+		}
+
+		blob := make([]byte, blobSize)
+		if _, err := io.ReadFull(r, blob); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPBF, err)
+		}
+		if blobType != "OSMData" {
+			// OSMHeader tidak berisi node maupun way
+			continue
+		}
+
+		data, err := unpackBlob(blob, &zr)
+		if err != nil {
+			return err
+		}
+		if err := decodePrimitiveBlock(data, onWay, onNode); err != nil {
+			return err
+		}
+	}
+}
+
+// unpackBlob returns the contents of a blob, reusing the zlib reader between blobs
+func unpackBlob(blob []byte, zr *io.ReadCloser) ([]byte, error) {
+	var rawSize uint64
+	p := pbMessage{buf: blob}
+	for p.next() {
+		switch p.field {
+		case 1:
+			return p.bytes(), p.err
+		case 2:
+			rawSize = p.varint()
+		case 3:
+			if rawSize > maxBlobSize {
+				return nil, fmt.Errorf("%w: blob of %d bytes", ErrInvalidPBF, rawSize)
+			}
+			compressed := bytes.NewReader(p.bytes())
+			var err error
+			if *zr == nil {
+				*zr, err = zlib.NewReader(compressed)
+			} else {
+				err = (*zr).(zlib.Resetter).Reset(compressed, nil)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidPBF, err)
+			}
+			data := make([]byte, 0, rawSize)
+			buf := bytes.NewBuffer(data)
+			if _, err := buf.ReadFrom(io.LimitReader(*zr, maxBlobSize)); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidPBF, err)
+			}
+			return buf.Bytes(), nil
+		case 4, 5, 6, 7:
+			return nil, fmt.Errorf("%w: only raw and zlib blobs are supported", ErrInvalidPBF)
+		default:
+			p.skip()
+		}
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return nil, fmt.Errorf("%w: empty blob", ErrInvalidPBF)
+}
+
+// primitiveBlock holds the fields a PrimitiveBlock shares with its groups
+type primitiveBlock struct {
+	strings     [][]byte
+	granularity int64
+	latOffset   int64
+	longOffset  int64
+}
+
+func (b *primitiveBlock) coord(offset, v int64) float64 {
+	return 1e-9 * float64(offset+b.granularity*v)
+}
+
+func decodePrimitiveBlock(data []byte, onWay func(refs []int64, tags map[string]string), onNode func(id int64, lat, long float64)) error {
+	block := primitiveBlock{granularity: 100}
+	var groups [][]byte
+
+	p := pbMessage{buf: data}
+	for p.next() {
+		switch p.field {
+		case 1:
+			st := pbMessage{buf: p.bytes()}
+			for st.next() {
+				if st.field == 1 {
+					block.strings = append(block.strings, st.bytes())
+				} else {
+					st.skip()
+				}
+			}
+			if st.err != nil {
+				return st.err
+			}
+		case 2:
+			// grup dibaca setelah granularity dan offset diketahui
+			groups = append(groups, p.bytes())
+		case 17:
+			block.granularity = int64(p.varint())
+		case 19:
+			block.latOffset = int64(p.varint())
+		case 20:
+			block.longOffset = int64(p.varint())
+		default:
+			p.skip()
+		}
+	}
+	if p.err != nil {
+		return p.err
+	}
+
+	for _, group := range groups {
+		g := pbMessage{buf: group}
+		for g.next() {
+			var err error
+			switch {
+			case g.field == 1 && onNode != nil:
+				err = block.decodeNode(g.bytes(), onNode)
+			case g.field == 2 && onNode != nil:
+				err = block.decodeDenseNodes(g.bytes(), onNode)
+			case g.field == 3 && onWay != nil:
+				err = block.decodeWay(g.bytes(), onWay)
+			default:
+				g.skip()
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if g.err != nil {
+			return g.err
+		}
+	}
+	return nil
+}
+
+func (b *primitiveBlock) decodeNode(data []byte, onNode func(id int64, lat, long float64)) error {
+	var id, lat, long int64
+	p := pbMessage{buf: data}
+	for p.next() {
+		switch p.field {
+		case 1:
+			id = p.sint()
+		case 8:
+			lat = p.sint()
+		case 9:
+			long = p.sint()
+		default:
+			p.skip()
+		}
+	}
+	if p.err != nil {
+		return p.err
+	}
+	onNode(id, b.coord(b.latOffset, lat), b.coord(b.longOffset, long))
+	return nil
+}
+
+func (b *primitiveBlock) decodeDenseNodes(data []byte, onNode func(id int64, lat, long float64)) error {
+	var ids, lats, longs []int64
+	p := pbMessage{buf: data}
+	for p.next() {
+		switch p.field {
+		case 1:
+			ids = p.packedSint(ids)
+		case 8:
+			lats = p.packedSint(lats)
+		case 9:
+			longs = p.packedSint(longs)
+		default:
+			p.skip()
+		}
+	}
+	if p.err != nil {
+		return p.err
+	}
+	if len(lats) != len(ids) || len(longs) != len(ids) {
+		return fmt.Errorf("%w: dense nodes have %d ids, %d lats and %d longs", ErrInvalidPBF, len(ids), len(lats), len(longs))
+	}
+
+	// id, lat dan long disimpan sebagai selisih dari node sebelumnya
+	var id, lat, long int64
+	for i := range ids {
+		id, lat, long = id+ids[i], lat+lats[i], long+longs[i]
+		onNode(id, b.coord(b.latOffset, lat), b.coord(b.longOffset, long))
+	}
+	return nil
+}
+
+func (b *primitiveBlock) decodeWay(data []byte, onWay func(refs []int64, tags map[string]string)) error {
+	var keys, vals, refs []int64
+	p := pbMessage{buf: data}
+	for p.next() {
+		switch p.field {
+		case 2:
+			keys = p.packedUint(keys)
+		case 3:
+			vals = p.packedUint(vals)
+		case 8:
+			refs = p.packedSint(refs)
+		default:
+			p.skip()
+		}
+	}
+	if p.err != nil {
+		return p.err
+	}
+	if len(keys) != len(vals) {
+		return fmt.Errorf("%w: way has %d keys and %d values", ErrInvalidPBF, len(keys), len(vals))
+	}
+
+	tags := make(map[string]string, len(keys))
+	for i := range keys {
+		if keys[i] < 0 || vals[i] < 0 || keys[i] >= int64(len(b.strings)) || vals[i] >= int64(len(b.strings)) {
+			return fmt.Errorf("%w: tag outside the string table", ErrInvalidPBF)
+		}
+		tags[string(b.strings[keys[i]])] = string(b.strings[vals[i]])
+	}
+	for i := 1; i < len(refs); i++ {
+		refs[i] += refs[i-1]
+	}
+	onWay(refs, tags)
+	return nil
+}
+
+// pbMessage iterates over the fields of a protobuf message
+type pbMessage struct {
+	buf   []byte
+	field int
+	wire  int
+	err   error
+}
+
+// next moves to the next field, returning false at the end of the message or on an error
+func (p *pbMessage) next() bool {
+	if p.err != nil || len(p.buf) == 0 {
+		return false
+	}
+	key := p.varint()
+	p.field, p.wire = int(key>>3), int(key&7)
+	return p.err == nil
+}
+
+func (p *pbMessage) varint() uint64 {
+	v, n := binary.Uvarint(p.buf)
+	if n <= 0 {
+		p.fail("malformed varint")
+		return 0
+	}
+	p.buf = p.buf[n:]
+	return v
+}
+
+// sint decodes a zigzag encoded sint64
+func (p *pbMessage) sint() int64 {
+	v := p.varint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (p *pbMessage) bytes() []byte {
+	n := p.varint()
+	if p.err != nil {
+		return nil
+	}
+	if n > uint64(len(p.buf)) {
+		p.fail("field runs past the end of the message")
+		return nil
+	}
+	v := p.buf[:n]
+	p.buf = p.buf[n:]
+	return v
+}
+
+func (p *pbMessage) skip() {
+	switch p.wire {
+	case wireVarint:
+		p.varint()
+	case wireBytes:
+		p.bytes()
+	case wireFixed64, wireFixed32:
+		n := 8
+		if p.wire == wireFixed32 {
+			n = 4
+		}
+		if len(p.buf) < n {
+			p.fail("field runs past the end of the message")
+			return
+		}
+		p.buf = p.buf[n:]
+	default:
+		p.fail(fmt.Sprintf("unsupported wire type %d", p.wire))
+	}
+}
+
+// packedUint appends the values of a repeated varint field, packed or not
+func (p *pbMessage) packedUint(dst []int64) []int64 {
+	if p.wire == wireVarint {
+		return append(dst, int64(p.varint()))
+	}
+	if p.wire != wireBytes {
+		p.fail("repeated field has wire type " + fmt.Sprint(p.wire))
+		return dst
+	}
+	packed := pbMessage{buf: p.bytes()}
+	for p.err == nil && packed.err == nil && len(packed.buf) > 0 {
+		dst = append(dst, int64(packed.varint()))
+	}
+	if packed.err != nil {
+		p.err = packed.err
+	}
+	return dst
+}
+
+// packedSint appends the values of a repeated sint64 field, packed or not
+func (p *pbMessage) packedSint(dst []int64) []int64 {
+	if p.wire == wireVarint {
+		return append(dst, p.sint())
+	}
+	if p.wire != wireBytes {
+		p.fail("repeated field has wire type " + fmt.Sprint(p.wire))
+		return dst
+	}
+	packed := pbMessage{buf: p.bytes()}
+	for p.err == nil && packed.err == nil && len(packed.buf) > 0 {
+		dst = append(dst, packed.sint())
+	}
+	if packed.err != nil {
+		p.err = packed.err
+	}
+	return dst
+}
+
+func (p *pbMessage) fail(msg string) {
+	if p.err == nil {
+		p.err = fmt.Errorf("%w: %s", ErrInvalidPBF, msg)
+	}
+	p.buf = nil
+}
@@ -0,0 +1,120 @@
+package purchase
+
+import (
+	"belimang/src/pkg/entities"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PriceChangedError is returned by Order when the current prices moved further from the
+// estimate than the tolerance allows. Quote is a new estimate at the current prices which
+// the user can order after confirming the new total.
+type PriceChangedError struct {
+	PreviousTotal entities.Money
+	Quote         *entities.DeliveryEstimate
+}
+
+func (e *PriceChangedError) Error() string {
+	return "prices have changed since the estimate, please confirm the new total"
+}
+
+// pricedLine is an order line as stored in delivery_estimate.priced_items, holding only
+// the priced fields Order writes back to order_items
+type pricedLine struct {
+	MerchantID      uuid.UUID       `json:"merchantId"`
+	ItemID          uuid.UUID       `json:"itemId"`
+	Quantity        int             `json:"quantity"`
+	ItemName        string          `json:"itemName"`
+	ProductCategory string          `json:"productCategory"`
+	UnitPrice       entities.Money  `json:"unitPrice"`
+	MerchantName    string          `json:"merchantName"`
+	Options         json.RawMessage `json:"options"`
+}
+
+// marshalPricedItems snapshots the priced order lines of an estimate
+func marshalPricedItems(items []entities.OrderItem) (json.RawMessage, error) {
+	lines := make([]pricedLine, len(items))
+	for i, item := range items {
+		lines[i] = pricedLine{
+			MerchantID:      item.MerchantID,
+			ItemID:          item.ItemID,
+			Quantity:        item.Quantity,
+			ItemName:        item.ItemName,
+			ProductCategory: item.ProductCategory,
+			UnitPrice:       item.UnitPrice,
+			MerchantName:    item.MerchantName,
+			Options:         item.Options,
+		}
+	}
+	return json.Marshal(lines)
+}
+
+// unmarshalPricedItems restores the order lines locked by an estimate
+func unmarshalPricedItems(data json.RawMessage) ([]entities.OrderItem, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var lines []pricedLine
+	if err := json.Unmarshal(data, &lines); err != nil {
+		return nil, err
+	}
+	items := make([]entities.OrderItem, len(lines))
+	for i, line := range lines {
+		items[i] = entities.OrderItem{
+			MerchantID:      line.MerchantID,
+			ItemID:          line.ItemID,
+			Quantity:        line.Quantity,
+			ItemName:        line.ItemName,
+			ProductCategory: line.ProductCategory,
+			UnitPrice:       line.UnitPrice,
+			MerchantName:    line.MerchantName,
+			Options:         line.Options,
+		}
+	}
+	return items, nil
+}
+
+// withinTolerance reports whether the current total is close enough to the locked total
+// for the estimate price to be honored
+func (s *service) withinTolerance(locked, current entities.Money) bool {
+	diff := current - locked
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= locked.Percent(s.config.PriceTolerancePercent)
+}
+
+// requote prices the lines of an estimate again at t and saves the result as a new estimate
+// with the same orders and route
+func (s *service) requote(est *entities.DeliveryEstimate, lines []orderLine, userID uuid.UUID, t time.Time) (*entities.DeliveryEstimate, error) {
+	priced, err := s.priceOrder(lines, t)
+	if err != nil {
+		return nil, err
+	}
+	pricedItems, err := marshalPricedItems(priced.OrderItems)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := *est
+	quote.ID = uuid.Nil
+	quote.TotalPrice = priced.Total
+	quote.PromotionDiscount = priced.Discount
+	quote.PromoCodeDiscount = 0
+	quote.CreatedAt = t.UTC()
+	quote.ExpiresAt = t.Add(s.config.EstimateTTL).UTC()
+	quote.ConsumedAt = nil
+	quote.PricedItems = pricedItems
+
+	if est.PromoCode != "" {
+		_, discount, err := s.applyPromoCode(est.PromoCode, userID, priced, t)
+		if err != nil {
+			return nil, err
+		}
+		quote.TotalPrice -= discount
+		quote.PromoCodeDiscount = discount
+	}
+	return s.repository.simpanEstimate(quote)
+}
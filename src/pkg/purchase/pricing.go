@@ -0,0 +1,148 @@
+package purchase
+
+import (
+	"belimang/src/pkg/entities"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// orderLine is a single ordered item together with its selected modifier options
+type orderLine struct {
+	MerchantID uuid.UUID
+	ItemID     uuid.UUID
+	Quantity   int
+	OptionIDs  []uuid.UUID
+}
+
+// pricedOrder is the outcome of pricing an order against the current catalog
+type pricedOrder struct {
+	OrderItems []entities.OrderItem
+	Subtotals  map[uuid.UUID]entities.Money // per merchant
+	Total      entities.Money
+	Discount   entities.Money // potongan dari promosi, sudah dikurangkan dari Total
+}
+
+// priceOrder validates the ordered lines against the current items and modifiers,
+// returning the order items to persist with the merchant subtotals and the total price.
+// Items are discounted by the promotions running at t.
+func (s *service) priceOrder(lines []orderLine, t time.Time) (*pricedOrder, error) {
+	quantities := make(map[uuid.UUID]int)
+	itemMerchant := make(map[uuid.UUID]uuid.UUID)
+	var itemsId []uuid.UUID
+	for _, line := range lines {
+		if _, ok := quantities[line.ItemID]; !ok {
+			itemsId = append(itemsId, line.ItemID)
+		}
+		quantities[line.ItemID] += line.Quantity
+		itemMerchant[line.ItemID] = line.MerchantID
+	}
+
+	//ambil items
+	items, err := s.repository.FindItemsById(itemsId)
+	if err != nil {
+		return nil, err
+	}
+	// item yang sudah dihapus atau bukan milik merchant tersebut ditolak
+	if err := checkItemsAvailable(itemMerchant, items); err != nil {
+		return nil, err
+	}
+	if err := checkStock(quantities, items); err != nil {
+		return nil, err
+	}
+
+	modifiers, err := s.repository.FindModifierGroups(itemsId)
+	if err != nil {
+		return nil, err
+	}
+
+	itemsById := make(map[uuid.UUID]entities.Items, len(items))
+	merchantIds := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		itemsById[item.ID] = item
+		merchantIds = append(merchantIds, item.MerchantID)
+	}
+	promotions, err := s.repository.FindActivePromotions(merchantIds, t)
+	if err != nil {
+		return nil, err
+	}
+
+	priced := &pricedOrder{
+		OrderItems: make([]entities.OrderItem, 0, len(lines)),
+		Subtotals:  make(map[uuid.UUID]entities.Money),
+	}
+	for _, line := range lines {
+		selected, priceDelta, err := selectOptions(line.ItemID, modifiers[line.ItemID], line.OptionIDs)
+		if err != nil {
+			return nil, err
+		}
+		options, err := json.Marshal(selected)
+		if err != nil {
+			return nil, err
+		}
+
+		item := itemsById[line.ItemID]
+		price, _ := entities.EffectivePrice(item, item.Merchant.Timezone, promotions[item.MerchantID], t)
+		unitPrice := price + priceDelta
+		lineTotal := unitPrice.Mul(line.Quantity)
+		priced.Discount += (item.Price - price).Mul(line.Quantity)
+		priced.Subtotals[line.MerchantID] += lineTotal
+		priced.Total += lineTotal
+		priced.OrderItems = append(priced.OrderItems, entities.OrderItem{
+			MerchantID:      line.MerchantID,
+			ItemID:          line.ItemID,
+			Quantity:        line.Quantity,
+			Options:         options,
+			ItemName:        item.Name,
+			ProductCategory: string(item.ProductCategory),
+			UnitPrice:       unitPrice,
+			MerchantName:    item.Merchant.Name,
+		})
+	}
+	return priced, nil
+}
+
+// selectOptions checks the chosen options against the modifier groups of an item and
+// returns the selection snapshot together with the summed price delta
+func selectOptions(itemID uuid.UUID, groups []entities.ModifierGroup, optionIDs []uuid.UUID) ([]entities.SelectedOption, entities.Money, error) {
+	type groupOption struct {
+		group  entities.ModifierGroup
+		option entities.ModifierOption
+	}
+	available := make(map[uuid.UUID]groupOption)
+	for _, g := range groups {
+		for _, o := range g.Options {
+			available[o.ID] = groupOption{group: g, option: o}
+		}
+	}
+
+	selected := make([]entities.SelectedOption, 0, len(optionIDs))
+	perGroup := make(map[uuid.UUID]int)
+	chosen := make(map[uuid.UUID]bool)
+	var priceDelta entities.Money
+	for _, id := range optionIDs {
+		opt, ok := available[id]
+		if !ok || chosen[id] {
+			return nil, 0, fmt.Errorf("invalid optionId: %s", id.String())
+		}
+		chosen[id] = true
+		perGroup[opt.group.ID]++
+		priceDelta += opt.option.PriceDelta
+		selected = append(selected, entities.SelectedOption{
+			GroupID:    opt.group.ID,
+			GroupName:  opt.group.Name,
+			OptionID:   opt.option.ID,
+			Name:       opt.option.Name,
+			PriceDelta: opt.option.PriceDelta,
+		})
+	}
+
+	for _, g := range groups {
+		if n := perGroup[g.ID]; n < g.MinSelect || n > g.MaxSelect {
+			return nil, 0, fmt.Errorf("itemId %s: %s requires between %d and %d options", itemID.String(), g.Name, g.MinSelect, g.MaxSelect)
+		}
+	}
+	return selected, priceDelta, nil
+}
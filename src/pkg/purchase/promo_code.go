@@ -0,0 +1,64 @@
+package purchase
+
+import (
+	"belimang/src/pkg/entities"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPromoCodeInvalid       = errors.New("promo code is invalid or expired")
+	ErrPromoCodeNotApplicable = errors.New("promo code is not valid for these merchants")
+	ErrPromoCodeUsedUp        = errors.New("promo code usage limit reached")
+)
+
+// applyPromoCode checks a promo code against the priced basket of a user at t and returns
+// the code with its discount. Only the subtotals of the allowed merchants count towards
+// the minimum spend and the discount.
+func (s *service) applyPromoCode(code string, userID uuid.UUID, priced *pricedOrder, t time.Time) (*entities.PromoCode, entities.Money, error) {
+	promoCode, err := s.repository.FindPromoCode(entities.NormalizePromoCode(code))
+	if err != nil {
+		return nil, 0, err
+	}
+	if promoCode == nil || !promoCode.ValidAt(t) {
+		return nil, 0, ErrPromoCodeInvalid
+	}
+
+	var spend entities.Money
+	for merchantID, subtotal := range priced.Subtotals {
+		if promoCode.AllowsMerchant(merchantID) {
+			spend += subtotal
+		}
+	}
+	if spend == 0 {
+		return nil, 0, ErrPromoCodeNotApplicable
+	}
+	if spend < promoCode.MinSpend {
+		return nil, 0, fmt.Errorf("minimum spend for promo code %s is %s", promoCode.Code, promoCode.MinSpend.String())
+	}
+
+	// pengecekan awal, batas pemakaian dicek ulang secara atomik saat order disimpan
+	used, usedByUser, err := s.repository.CountRedemptions(promoCode.ID, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !withinLimits(promoCode, used, usedByUser) {
+		return nil, 0, ErrPromoCodeUsedUp
+	}
+
+	return promoCode, promoCode.Discount(spend), nil
+}
+
+// withinLimits reports whether the code may be used once more
+func withinLimits(promoCode *entities.PromoCode, used, usedByUser int64) bool {
+	if promoCode.UsageLimit != nil && used >= int64(*promoCode.UsageLimit) {
+		return false
+	}
+	if promoCode.PerUserLimit != nil && usedByUser >= int64(*promoCode.PerUserLimit) {
+		return false
+	}
+	return true
+}
@@ -3,13 +3,16 @@ package purchase
 import (
 	"belimang/src/pkg/dtos"
 	"belimang/src/pkg/entities"
-	"encoding/json"
+	"belimang/src/pkg/merchant"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Repository interface allows us to access the CRUD Operations here.
@@ -20,8 +23,14 @@ type Repository interface {
 	simpanEstimate(req entities.DeliveryEstimate) (*entities.DeliveryEstimate, error)
 	FindItemsById(itemIDs []uuid.UUID) ([]entities.Items, error)
 	FindEstimateById(estimateID uuid.UUID) (*entities.DeliveryEstimate, error)
-	SimpanOrders(req entities.DeliveryEstimate, userID uuid.UUID, TotalHarga float64) (string, error)
+	SimpanOrders(estimateID, userID uuid.UUID, TotalHarga entities.Money, orderItems []entities.OrderItem, redemption *entities.PromoCodeRedemption) (string, error)
 	FindOrders(req map[string]interface{}) ([]dtos.OrderDetail, error)
+	FindRatings(merchantIDs []uuid.UUID) (map[uuid.UUID]dtos.RatingSummary, error)
+	FindSchedules(merchantIDs []uuid.UUID) (map[uuid.UUID][]entities.OpeningHours, map[uuid.UUID][]entities.MerchantClosure, error)
+	FindModifierGroups(itemIDs []uuid.UUID) (map[uuid.UUID][]entities.ModifierGroup, error)
+	FindActivePromotions(merchantIDs []uuid.UUID, t time.Time) (map[uuid.UUID][]entities.Promotion, error)
+	FindPromoCode(code string) (*entities.PromoCode, error)
+	CountRedemptions(promoCodeID, userID uuid.UUID) (int64, int64, error)
 }
 type repository struct {
 	DB *gorm.DB
@@ -79,7 +88,7 @@ func (r *repository) NearbyMerchant(userLat, userLong float64, params map[string
 		if v != "" {
 			name := "%" + strings.ToLower(v.(string)) + "%"
 			query = query.Where("(LOWER(m.name) LIKE ? OR EXISTS ("+
-				"SELECT 1 FROM items i WHERE i.merchant_id = m.id AND LOWER(i.name) LIKE ?"+
+				"SELECT 1 FROM items i WHERE i.merchant_id = m.id AND i.deleted_at IS NULL AND LOWER(i.name) LIKE ?"+
 				"))", name, name)
 		}
 	}
@@ -90,6 +99,13 @@ func (r *repository) NearbyMerchant(userLat, userLong float64, params map[string
 			query = query.Where("merchant_category = ?", v)
 		}
 	}
+	// hanya merchant yang punya item dengan semua tag yang diminta
+	if tags, ok := params["tags"].([]string); ok && len(tags) > 0 {
+		query = query.Where("EXISTS ("+
+			"SELECT 1 FROM items i WHERE i.merchant_id = m.id AND i.deleted_at IS NULL AND "+
+			"(SELECT COUNT(*) FROM item_tags t WHERE t.item_id = i.id AND t.tag IN ?) = ?"+
+			")", tags, len(tags))
+	}
 
 	// Order by nearest & limit
 	err := query. //Offset(params["offset"].(int)).Limit(params["limit"].(int)).
@@ -104,7 +120,9 @@ func (r *repository) NearbyMerchant(userLat, userLong float64, params map[string
 func (r *repository) GetItemsByMerchantIDs(merchantIDs []uuid.UUID) (map[uuid.UUID][]entities.Items, error) {
 	var items []entities.Items
 
-	err := r.DB.Where("merchant_id IN ?", merchantIDs).Find(&items).Error
+	err := r.DB.Preload("Tags", func(db *gorm.DB) *gorm.DB {
+		return db.Order("tag")
+	}).Where("merchant_id IN ?", merchantIDs).Find(&items).Error
 	if err != nil {
 		return nil, err
 	}
@@ -122,16 +140,34 @@ func (r *repository) FindMerchantById(merchantIDs []uuid.UUID) ([]entities.Merch
 	var merchant []entities.Merchant
 	if err := r.DB.
 		Table("merchants").
-		Where("id IN ?", merchantIDs).Scan(&merchant).Error; err != nil {
+		Where("id IN ? AND deleted_at IS NULL", merchantIDs).Scan(&merchant).Error; err != nil {
 		return nil, err
 	}
 	return merchant, nil
 }
+
+// FindSchedules loads the opening hours and closures of the given merchants keyed by merchant ID
+func (r *repository) FindSchedules(merchantIDs []uuid.UUID) (map[uuid.UUID][]entities.OpeningHours, map[uuid.UUID][]entities.MerchantClosure, error) {
+	return merchant.FindSchedules(r.DB, merchantIDs)
+}
+
+// FindModifierGroups loads the modifier groups with their options of the given items keyed by item ID
+func (r *repository) FindModifierGroups(itemIDs []uuid.UUID) (map[uuid.UUID][]entities.ModifierGroup, error) {
+	return merchant.FindModifierGroups(r.DB, itemIDs)
+}
+
+// FindActivePromotions loads the promotions of the given merchants whose date range contains t,
+// keyed by merchant ID. Daily windows are left to Promotion.ActiveAt.
+func (r *repository) FindActivePromotions(merchantIDs []uuid.UUID, t time.Time) (map[uuid.UUID][]entities.Promotion, error) {
+	return merchant.FindActivePromotions(r.DB, merchantIDs, t)
+}
+
 func (r *repository) FindItemsById(itemIDs []uuid.UUID) ([]entities.Items, error) {
 	var items []entities.Items
+	// merchant ikut dimuat untuk snapshot order_items
 	if err := r.DB.
-		Table("items").
-		Where("id IN ?", itemIDs).Scan(&items).Error; err != nil {
+		Preload("Merchant").
+		Where("id IN ?", itemIDs).Find(&items).Error; err != nil {
 		return nil, err
 	}
 	return items, nil
@@ -157,18 +193,54 @@ func (r *repository) simpanEstimate(req entities.DeliveryEstimate) (*entities.De
 	return &req, nil
 }
 
-func (r *repository) SimpanOrders(req entities.DeliveryEstimate, userID uuid.UUID, TotalHarga float64) (string, error) {
+// FindPromoCode retrieves a promo code with its allowed merchants, returning nil when it does not exist
+func (r *repository) FindPromoCode(code string) (*entities.PromoCode, error) {
+	var promoCode entities.PromoCode
+	if err := r.DB.Preload("Merchants").Where("code = ?", code).First(&promoCode).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &promoCode, nil
+}
+
+// CountRedemptions counts how often a promo code has been used in total and by the user
+func (r *repository) CountRedemptions(promoCodeID, userID uuid.UUID) (int64, int64, error) {
+	return countRedemptions(r.DB, promoCodeID, userID)
+}
+
+func countRedemptions(db *gorm.DB, promoCodeID, userID uuid.UUID) (int64, int64, error) {
+	var row struct {
+		Used       int64
+		UsedByUser int64
+	}
+	err := db.
+		Table("promo_code_redemptions").
+		Select("COUNT(*) AS used, COUNT(*) FILTER (WHERE user_id = ?) AS used_by_user", userID).
+		Where("promo_code_id = ?", promoCodeID).
+		Scan(&row).Error
+	return row.Used, row.UsedByUser, err
+}
+
+func (r *repository) SimpanOrders(estimateID, userID uuid.UUID, TotalHarga entities.Money, orderItems []entities.OrderItem, redemption *entities.PromoCodeRedemption) (string, error) {
 	OrderID := uuid.New()
 	// UserID := userID
-	var wrappers []entities.OrderWrapper
-	if err := json.Unmarshal(req.Orders, &wrappers); err != nil {
-		return "", err
-	}
 
 	err := r.DB.Transaction(func(tx *gorm.DB) error {
 		var allOrders []entities.Order
 		var allOrderItems []entities.OrderItem
 
+		// tandai estimasi terpakai, order bersamaan untuk estimasi yang sama hanya satu yang lolos
+		if err := consumeEstimate(tx, estimateID); err != nil {
+			return err
+		}
+
+		// kurangi stok secara atomik, item yang stoknya tidak cukup dikumpulkan
+		if err := decrementStock(tx, orderItems); err != nil {
+			return err
+		}
+
 		orderID := OrderID
 		order := entities.Order{
 			ID:         orderID,
@@ -178,18 +250,11 @@ func (r *repository) SimpanOrders(req entities.DeliveryEstimate, userID uuid.UUI
 		}
 		allOrders = append(allOrders, order)
 
-		for _, w := range wrappers {
-			// siapkan order_items untuk batch insert
-			for _, item := range w.Items {
-				orderItem := entities.OrderItem{
-					ID:         uuid.New(),
-					MerchantID: w.MerchantID,
-					OrderID:    orderID,
-					ItemID:     item.ItemID,
-					Quantity:   item.Quantity,
-				}
-				allOrderItems = append(allOrderItems, orderItem)
-			}
+		// siapkan order_items untuk batch insert
+		for _, item := range orderItems {
+			item.ID = uuid.New()
+			item.OrderID = orderID
+			allOrderItems = append(allOrderItems, item)
 		}
 
 		// batch insert orders
@@ -202,11 +267,99 @@ func (r *repository) SimpanOrders(req entities.DeliveryEstimate, userID uuid.UUI
 			return err
 		}
 
+		if redemption != nil {
+			redemption.OrderID = orderID
+			return redeemPromoCode(tx, redemption)
+		}
 		return nil
 	})
 	return OrderID.String(), err
 }
 
+// consumeEstimate marks an unexpired estimate as ordered, failing when it was used or expired meanwhile
+func consumeEstimate(tx *gorm.DB, estimateID uuid.UUID) error {
+	now := time.Now().UTC()
+	result := tx.Exec(`UPDATE delivery_estimate SET consumed_at = ?
+		WHERE id = ? AND consumed_at IS NULL AND expires_at > ?`, now, estimateID, now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var consumed int64
+		if err := tx.Table("delivery_estimate").
+			Where("id = ? AND consumed_at IS NOT NULL", estimateID).
+			Count(&consumed).Error; err != nil {
+			return err
+		}
+		if consumed > 0 {
+			return ErrEstimateUsed
+		}
+		return ErrEstimateExpired
+	}
+	return nil
+}
+
+// redeemPromoCode records the use of a promo code. The code row is locked so concurrent
+// orders cannot use it beyond its limits.
+func redeemPromoCode(tx *gorm.DB, redemption *entities.PromoCodeRedemption) error {
+	var promoCode entities.PromoCode
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", redemption.PromoCodeID).
+		First(&promoCode).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPromoCodeInvalid
+		}
+		return err
+	}
+	if !promoCode.IsActive {
+		return ErrPromoCodeInvalid
+	}
+
+	used, usedByUser, err := countRedemptions(tx, promoCode.ID, redemption.UserID)
+	if err != nil {
+		return err
+	}
+	if !withinLimits(&promoCode, used, usedByUser) {
+		return ErrPromoCodeUsedUp
+	}
+	return tx.Create(redemption).Error
+}
+
+// decrementStock subtracts the ordered quantities from the tracked stock of each item.
+// Items are locked in a fixed order to avoid deadlocks between concurrent orders.
+func decrementStock(tx *gorm.DB, orderItems []entities.OrderItem) error {
+	quantities := make(map[uuid.UUID]int)
+	for _, item := range orderItems {
+		quantities[item.ItemID] += item.Quantity
+	}
+
+	itemIDs := make([]uuid.UUID, 0, len(quantities))
+	for id := range quantities {
+		itemIDs = append(itemIDs, id)
+	}
+	sort.Slice(itemIDs, func(i, j int) bool {
+		return itemIDs[i].String() < itemIDs[j].String()
+	})
+
+	var outOfStock []uuid.UUID
+	for _, id := range itemIDs {
+		qty := quantities[id]
+		result := tx.Exec(`UPDATE items SET stock = stock - ?
+			WHERE id = ? AND is_available AND deleted_at IS NULL AND (stock IS NULL OR stock >= ?)`, qty, id, qty)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			outOfStock = append(outOfStock, id)
+		}
+	}
+
+	if len(outOfStock) > 0 {
+		return &OutOfStockError{ItemIDs: outOfStock}
+	}
+	return nil
+}
+
 func (r *repository) FindOrders(params map[string]interface{}) ([]dtos.OrderDetail, error) {
 
 	var orders []dtos.OrderDetail
@@ -214,22 +367,24 @@ func (r *repository) FindOrders(params map[string]interface{}) ([]dtos.OrderDeta
 		Table("orders AS o").
 		Select(`o.id AS order_id, 
 	        oi.merchant_id, 
-	        m.name AS merchant_name, 
-	        m.merchant_category, 
-	        m.image_url AS merchant_image_url, 
-	        m.long AS merchant_long, 
-	        m.lat AS merchant_lat, 
-			m.created_at AS merchant_created_at,
+	        oi.merchant_name, 
+	        COALESCE(m.merchant_category, '') AS merchant_category, 
+	        COALESCE(m.image_url, '') AS merchant_image_url, 
+	        COALESCE(m.long, 0) AS merchant_long, 
+	        COALESCE(m.lat, 0) AS merchant_lat, 
+			COALESCE(m.created_at, 0) AS merchant_created_at,
 	        oi.item_id, 
-	        i.name AS item_name, 
-	        i.product_category, 
-	        i.price, 
+	        oi.item_name, 
+	        oi.product_category, 
+	        oi.unit_price AS price, 
 	        oi.quantity, 
-	        i.image_url AS item_image_url, 
-			i.created_at AS item_created_at`).
+	        oi.options, 
+	        COALESCE(i.image_url, '') AS item_image_url, 
+			COALESCE(i.created_at, 0) AS item_created_at`).
 		Joins("JOIN order_items oi ON o.id = oi.order_id").
-		Joins("JOIN merchants m ON m.id = oi.merchant_id").
-		Joins("JOIN items i ON oi.item_id = i.id")
+		// nama dan harga diambil dari snapshot, merchant atau item yang sudah dihapus tetap tampil
+		Joins("LEFT JOIN merchants m ON m.id = oi.merchant_id").
+		Joins("LEFT JOIN items i ON oi.item_id = i.id")
 
 	if v, ok := params["merchantId"]; ok {
 		if v != "" {
@@ -238,17 +393,17 @@ func (r *repository) FindOrders(params map[string]interface{}) ([]dtos.OrderDeta
 				id, err := uuid.Parse(val)
 				if err == nil {
 					merchantId := []uuid.UUID{id}
-					query = query.Where("m.id IN ?", merchantId)
+					query = query.Where("oi.merchant_id IN ?", merchantId)
 
 				} else {
 					return nil, fmt.Errorf("invalid merchantId: %s", val)
 				}
 			case uuid.UUID:
 				merchantId := []uuid.UUID{val}
-				query = query.Where("m.id IN ?", merchantId)
+				query = query.Where("oi.merchant_id IN ?", merchantId)
 
 			case []uuid.UUID:
-				query = query.Where("m.id IN ?", val)
+				query = query.Where("oi.merchant_id IN ?", val)
 
 			default:
 				return nil, fmt.Errorf("invalid merchantId: %v", v)
@@ -259,8 +414,8 @@ func (r *repository) FindOrders(params map[string]interface{}) ([]dtos.OrderDeta
 	if v, ok := params["name"]; ok {
 		if v != "" {
 			name := "%" + strings.ToLower(v.(string)) + "%"
-			query = query.Where("(LOWER(m.name) LIKE ? OR EXISTS ("+
-				"SELECT 1 FROM items i WHERE i.merchant_id = m.id AND LOWER(i.name) LIKE ?"+
+			query = query.Where("(LOWER(oi.merchant_name) LIKE ? OR EXISTS ("+
+				"SELECT 1 FROM order_items oi2 WHERE oi2.order_id = o.id AND oi2.merchant_id = oi.merchant_id AND LOWER(oi2.item_name) LIKE ?"+
 				"))", name, name)
 		}
 	}
@@ -278,3 +433,8 @@ func (r *repository) FindOrders(params map[string]interface{}) ([]dtos.OrderDeta
 
 	return orders, nil
 }
+
+// FindRatings aggregates the visible reviews of the given merchants keyed by merchant ID
+func (r *repository) FindRatings(merchantIDs []uuid.UUID) (map[uuid.UUID]dtos.RatingSummary, error) {
+	return merchant.FindRatings(r.DB, merchantIDs)
+}
@@ -0,0 +1,403 @@
+package purchase
+
+import (
+	"container/heap"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// maxSnapKm is how far a location may lie from the nearest road node, locations further
+// away fall back to straight-line distances
+const maxSnapKm = 1.0
+
+// gridCellDeg is the size of the cells of the spatial index used to snap locations to nodes
+const gridCellDeg = 0.01
+
+// skippedHighways are ways that delivery vehicles cannot use
+var skippedHighways = map[string]bool{
+	"footway": true, "pedestrian": true, "steps": true, "path": true, "bridleway": true,
+	"corridor": true, "platform": true, "elevator": true, "construction": true, "proposed": true,
+	"abandoned": true, "raceway": true, "bus_guideway": true,
+}
+
+type roadEdge struct {
+	to int32
+	km float64
+}
+
+type gridCell struct {
+	lat, long int32
+}
+
+// RoadNetwork is a road graph loaded from an OSM extract, it measures distances along
+// the roads with Dijkstra and falls back to straight-line distances where the graph does not reach
+type RoadNetwork struct {
+	lats  []float64
+	longs []float64
+	edges [][]roadEdge
+	grid  map[gridCell][]int32
+
+	searches sync.Pool // *searchState, dipakai ulang antar pencarian
+}
+
+// osmDecoder reads an OSM extract, calling onWay for every way and onNode for every node.
+// A nil callback skips that kind of element.
+type osmDecoder func(r io.Reader, onWay func(refs []int64, tags map[string]string), onNode func(id int64, lat, long float64)) error
+
+// LoadRoadNetwork reads the road graph of an OSM extract, either PBF (.osm.pbf) or XML (.osm)
+func LoadRoadNetwork(path string) (*RoadNetwork, error) {
+	decode := decodeOSMXML
+	if strings.EqualFold(filepath.Ext(path), ".pbf") {
+		decode = decodeOSMPBF
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readRoadNetwork(f, decode)
+}
+
+// readRoadNetwork reads the extract twice: the roads first, then only the coordinates of the
+// nodes those roads use, so the other nodes of the extract are never held in memory
+func readRoadNetwork(r io.ReadSeeker, decode osmDecoder) (*RoadNetwork, error) {
+	b := &graphBuilder{index: make(map[int64]int32)}
+	if err := decode(r, b.addWay, nil); err != nil {
+		return nil, err
+	}
+	if len(b.index) == 0 {
+		return nil, fmt.Errorf("no roads found in OSM extract")
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	b.lats = make([]float64, len(b.index))
+	b.longs = make([]float64, len(b.index))
+	b.found = make([]bool, len(b.index))
+	if err := decode(r, nil, b.setNode); err != nil {
+		return nil, err
+	}
+	return b.build()
+}
+
+// graphBuilder collects the roads of an extract before their node coordinates are known
+type graphBuilder struct {
+	index map[int64]int32 // id node OSM -> index sementara
+	ways  []roadWay
+
+	lats, longs []float64
+	found       []bool
+}
+
+type roadWay struct {
+	nodes             []int32
+	forward, backward bool
+}
+
+func (b *graphBuilder) addWay(refs []int64, tags map[string]string) {
+	highway := tags["highway"]
+	if highway == "" || skippedHighways[highway] || tags["access"] == "no" || tags["access"] == "private" {
+		return
+	}
+
+	w := roadWay{nodes: make([]int32, len(refs))}
+	w.forward, w.backward = wayDirections(tags)
+	for i, ref := range refs {
+		idx, ok := b.index[ref]
+		if !ok {
+			idx = int32(len(b.index))
+			b.index[ref] = idx
+		}
+		w.nodes[i] = idx
+	}
+	b.ways = append(b.ways, w)
+}
+
+func (b *graphBuilder) setNode(id int64, lat, long float64) {
+	if i, ok := b.index[id]; ok {
+		b.lats[i], b.longs[i], b.found[i] = lat, long, true
+	}
+}
+
+// build links the roads, nodes missing from the extract split their way
+func (b *graphBuilder) build() (*RoadNetwork, error) {
+	g := &RoadNetwork{grid: make(map[gridCell][]int32)}
+	compact := make([]int32, len(b.found))
+	for i, ok := range b.found {
+		compact[i] = -1
+		if !ok {
+			continue
+		}
+		n := int32(len(g.lats))
+		compact[i] = n
+		g.lats = append(g.lats, b.lats[i])
+		g.longs = append(g.longs, b.longs[i])
+		cell := cellOf(b.lats[i], b.longs[i])
+		g.grid[cell] = append(g.grid[cell], n)
+	}
+	if len(g.lats) == 0 {
+		return nil, fmt.Errorf("no roads found in OSM extract")
+	}
+
+	g.edges = make([][]roadEdge, len(g.lats))
+	for _, w := range b.ways {
+		prev := int32(-1)
+		for _, node := range w.nodes {
+			cur := compact[node]
+			if cur < 0 {
+				// node di luar extract
+				prev = -1
+				continue
+			}
+			if prev >= 0 && prev != cur {
+				km := Haversine(g.lats[prev], g.longs[prev], g.lats[cur], g.longs[cur])
+				if w.forward {
+					g.edges[prev] = append(g.edges[prev], roadEdge{to: cur, km: km})
+				}
+				if w.backward {
+					g.edges[cur] = append(g.edges[cur], roadEdge{to: prev, km: km})
+				}
+			}
+			prev = cur
+		}
+	}
+	return g, nil
+}
+
+type osmNode struct {
+	ID   int64   `xml:"id,attr"`
+	Lat  float64 `xml:"lat,attr"`
+	Long float64 `xml:"lon,attr"`
+}
+
+type osmWay struct {
+	Nodes []struct {
+		Ref int64 `xml:"ref,attr"`
+	} `xml:"nd"`
+	Tags []struct {
+		Key   string `xml:"k,attr"`
+		Value string `xml:"v,attr"`
+	} `xml:"tag"`
+}
+
+// decodeOSMXML reads an OSM XML extract
+func decodeOSMXML(r io.Reader, onWay func(refs []int64, tags map[string]string), onNode func(id int64, lat, long float64)) error {
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case start.Name.Local == "node" && onNode != nil:
+			var n osmNode
+			if err := decoder.DecodeElement(&n, &start); err != nil {
+				return err
+			}
+			onNode(n.ID, n.Lat, n.Long)
+		case start.Name.Local == "way" && onWay != nil:
+			var w osmWay
+			if err := decoder.DecodeElement(&w, &start); err != nil {
+				return err
+			}
+			refs := make([]int64, len(w.Nodes))
+			for i, nd := range w.Nodes {
+				refs[i] = nd.Ref
+			}
+			tags := make(map[string]string, len(w.Tags))
+			for _, t := range w.Tags {
+				tags[t.Key] = t.Value
+			}
+			onWay(refs, tags)
+		case start.Name.Local == "node", start.Name.Local == "way", start.Name.Local == "relation":
+			if err := decoder.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// wayDirections reports in which directions a way may be driven
+func wayDirections(tags map[string]string) (forward, backward bool) {
+	switch strings.ToLower(tags["oneway"]) {
+	case "yes", "true", "1":
+		return true, false
+	case "-1", "reverse":
+		return false, true
+	case "no", "false", "0":
+		return true, true
+	}
+	if tags["junction"] == "roundabout" || tags["highway"] == "motorway" {
+		return true, false
+	}
+	return true, true
+}
+
+func cellOf(lat, long float64) gridCell {
+	return gridCell{lat: int32(math.Floor(lat / gridCellDeg)), long: int32(math.Floor(long / gridCellDeg))}
+}
+
+// Nodes returns the number of nodes in the graph
+func (g *RoadNetwork) Nodes() int {
+	return len(g.lats)
+}
+
+// snap finds the node nearest to a location within maxSnapKm, returning -1 when there is none
+func (g *RoadNetwork) snap(lat, long float64) (int32, float64) {
+	center := cellOf(lat, long)
+	// satu sel kira-kira 1.1 km, cukup periksa sel di sekitarnya
+	rings := int32(math.Ceil(maxSnapKm/(gridCellDeg*111))) + 1
+
+	best, bestKm := int32(-1), maxSnapKm
+	for dLat := -rings; dLat <= rings; dLat++ {
+		for dLong := -rings; dLong <= rings; dLong++ {
+			for _, i := range g.grid[gridCell{lat: center.lat + dLat, long: center.long + dLong}] {
+				if km := Haversine(lat, long, g.lats[i], g.longs[i]); km <= bestKm {
+					best, bestKm = i, km
+				}
+			}
+		}
+	}
+	return best, bestKm
+}
+
+// Distances measures the road distance between every pair of locations with one search per
+// location. Pairs the graph cannot connect use the straight-line distance.
+func (g *RoadNetwork) Distances(points []Point) ([][]float64, error) {
+	nodes := make([]int32, len(points))
+	snapKm := make([]float64, len(points))
+	var targets []int32
+	for i, p := range points {
+		nodes[i], snapKm[i] = g.snap(p.Lat, p.Long)
+		if nodes[i] >= 0 {
+			targets = append(targets, nodes[i])
+		}
+	}
+
+	dist := make([][]float64, len(points))
+	for i := range dist {
+		dist[i] = make([]float64, len(points))
+		var reached map[int32]float64
+		if nodes[i] >= 0 {
+			reached = g.shortestPaths(nodes[i], targets)
+		}
+		for j := range dist[i] {
+			if i == j {
+				continue
+			}
+			straight := Haversine(points[i].Lat, points[i].Long, points[j].Lat, points[j].Long)
+			dist[i][j] = straight
+			if nodes[j] < 0 {
+				continue
+			}
+			if km, ok := reached[nodes[j]]; ok {
+				// jalan tidak pernah lebih pendek dari garis lurus
+				dist[i][j] = math.Max(snapKm[i]+km+snapKm[j], straight)
+			}
+		}
+	}
+	return dist, nil
+}
+
+// searchState holds the per node labels of a Dijkstra search. Labels are only valid when
+// their stamp matches gen, so a state is reused without clearing it.
+type searchState struct {
+	km    []float64
+	stamp []uint32
+	done  []bool
+	gen   uint32
+	queue nodeQueue
+}
+
+func (g *RoadNetwork) searchState() *searchState {
+	if st, ok := g.searches.Get().(*searchState); ok {
+		st.gen++
+		if st.gen == 0 {
+			// stamp berputar, semua label lama dibuang
+			clear(st.stamp)
+			st.gen = 1
+		}
+		st.queue = st.queue[:0]
+		return st
+	}
+	return &searchState{
+		km:    make([]float64, len(g.lats)),
+		stamp: make([]uint32, len(g.lats)),
+		done:  make([]bool, len(g.lats)),
+		gen:   1,
+	}
+}
+
+// shortestPaths runs Dijkstra from one node until every target is settled, returning the
+// distance of each target it reached
+func (g *RoadNetwork) shortestPaths(from int32, targets []int32) map[int32]float64 {
+	st := g.searchState()
+	defer g.searches.Put(st)
+
+	pending := make(map[int32]bool, len(targets))
+	for _, t := range targets {
+		pending[t] = true
+	}
+	reached := make(map[int32]float64, len(targets))
+
+	label := func(n int32, km float64) {
+		st.km[n], st.stamp[n], st.done[n] = km, st.gen, false
+		heap.Push(&st.queue, nodeEntry{node: n, priority: km})
+	}
+	label(from, 0)
+	for st.queue.Len() > 0 && len(pending) > 0 {
+		cur := heap.Pop(&st.queue).(nodeEntry)
+		if st.done[cur.node] || cur.priority > st.km[cur.node] {
+			continue
+		}
+		st.done[cur.node] = true
+		if pending[cur.node] {
+			reached[cur.node] = cur.priority
+			delete(pending, cur.node)
+		}
+
+		for _, e := range g.edges[cur.node] {
+			km := cur.priority + e.km
+			if st.stamp[e.to] == st.gen && st.km[e.to] <= km {
+				continue
+			}
+			label(e.to, km)
+		}
+	}
+	return reached
+}
+
+type nodeEntry struct {
+	node     int32
+	priority float64
+}
+
+type nodeQueue []nodeEntry
+
+func (q nodeQueue) Len() int            { return len(q) }
+func (q nodeQueue) Less(i, j int) bool  { return q[i].priority < q[j].priority }
+func (q nodeQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *nodeQueue) Push(x interface{}) { *q = append(*q, x.(nodeEntry)) }
+func (q *nodeQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	x := old[n-1]
+	*q = old[:n-1]
+	return x
+}
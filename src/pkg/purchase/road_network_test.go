@@ -0,0 +1,329 @@
+package purchase
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type fixtureNode struct {
+	id         int64
+	lat, long  float64
+	pointOnly  bool // node tanpa way, mis. POI
+	denseGroup bool
+}
+
+type fixtureWay struct {
+	refs []int64
+	tags map[string]string
+}
+
+// fixtureNodes lay out a small street grid south of central Jakarta:
+//
+//	A --> B --> C   one-way primary road, eastbound only
+//	|  ·········|   footway from C to A that vehicles cannot use
+//	E --------- D   two-way residential street, the only way back west
+//
+// H - I is a road that is not connected to the rest
+var (
+	nodeA = fixtureNode{id: 1, lat: -6.2000, long: 106.8000, denseGroup: true}
+	nodeB = fixtureNode{id: 2, lat: -6.2000, long: 106.8100, denseGroup: true}
+	nodeC = fixtureNode{id: 3, lat: -6.2000, long: 106.8200, denseGroup: true}
+	nodeD = fixtureNode{id: 4, lat: -6.2100, long: 106.8200, denseGroup: true}
+	nodeE = fixtureNode{id: 5, lat: -6.2100, long: 106.8000, denseGroup: true}
+	nodeH = fixtureNode{id: 6, lat: -6.3000, long: 106.9000}
+	nodeI = fixtureNode{id: 7, lat: -6.3000, long: 106.9100}
+
+	fixtureNodes = []fixtureNode{
+		nodeA, nodeB, nodeC, nodeD, nodeE, nodeH, nodeI,
+		{id: 8, lat: -6.2010, long: 106.8200, denseGroup: true},
+		{id: 9, lat: -6.2010, long: 106.8000, denseGroup: true},
+		{id: 10, lat: -6.2020, long: 106.8050, pointOnly: true},
+	}
+
+	fixtureWays = []fixtureWay{
+		{refs: []int64{1, 2, 3}, tags: map[string]string{"highway": "primary", "oneway": "yes"}},
+		{refs: []int64{3, 4, 5, 1}, tags: map[string]string{"highway": "residential"}},
+		{refs: []int64{3, 8, 9, 1}, tags: map[string]string{"highway": "footway"}},
+		{refs: []int64{6, 7}, tags: map[string]string{"highway": "residential"}},
+		// node 99 tidak ada di extract
+		{refs: []int64{1, 99}, tags: map[string]string{"highway": "service"}},
+	}
+)
+
+func (n fixtureNode) point() Point {
+	return Point{Lat: n.lat, Long: n.long}
+}
+
+func between(a, b fixtureNode) float64 {
+	return Haversine(a.lat, a.long, b.lat, b.long)
+}
+
+func fixtureXML() []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<osm version="0.6">` + "\n")
+	for _, n := range fixtureNodes {
+		fmt.Fprintf(&buf, `  <node id="%d" lat="%.7f" lon="%.7f"`, n.id, n.lat, n.long)
+		if n.pointOnly {
+			buf.WriteString(">\n    <tag k=\"amenity\" v=\"restaurant\"/>\n  </node>\n")
+			continue
+		}
+		buf.WriteString("/>\n")
+	}
+	for i, w := range fixtureWays {
+		fmt.Fprintf(&buf, "  <way id=\"%d\">\n", 100+i)
+		for _, ref := range w.refs {
+			fmt.Fprintf(&buf, "    <nd ref=\"%d\"/>\n", ref)
+		}
+		for k, v := range w.tags {
+			fmt.Fprintf(&buf, "    <tag k=%q v=%q/>\n", k, v)
+		}
+		buf.WriteString("  </way>\n")
+	}
+	buf.WriteString(`  <relation id="200"><member type="way" ref="100" role=""/></relation>` + "\n</osm>\n")
+	return buf.Bytes()
+}
+
+// protobuf encoding helpers for building PBF fixtures
+
+func pbKey(b []byte, field, wire int) []byte {
+	return binary.AppendUvarint(b, uint64(field<<3|wire))
+}
+
+func pbUint(b []byte, field int, v uint64) []byte {
+	return binary.AppendUvarint(pbKey(b, field, wireVarint), v)
+}
+
+func pbBytes(b []byte, field int, v []byte) []byte {
+	b = binary.AppendUvarint(pbKey(b, field, wireBytes), uint64(len(v)))
+	return append(b, v...)
+}
+
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+// pbDeltas writes a packed sint64 field holding the differences between consecutive values
+func pbDeltas(b []byte, field int, vals []int64) []byte {
+	var packed []byte
+	var prev int64
+	for _, v := range vals {
+		packed = binary.AppendUvarint(packed, zigzag(v-prev))
+		prev = v
+	}
+	return pbBytes(b, field, packed)
+}
+
+func pbBlob(w *bytes.Buffer, blobType string, data []byte, compress bool) {
+	var blob []byte
+	if compress {
+		var z bytes.Buffer
+		zw := zlib.NewWriter(&z)
+		zw.Write(data)
+		zw.Close()
+		blob = pbUint(blob, 2, uint64(len(data)))
+		blob = pbBytes(blob, 3, z.Bytes())
+	} else {
+		blob = pbBytes(blob, 1, data)
+	}
+
+	header := pbBytes(nil, 1, []byte(blobType))
+	header = pbUint(header, 3, uint64(len(blob)))
+	binary.Write(w, binary.BigEndian, uint32(len(header)))
+	w.Write(header)
+	w.Write(blob)
+}
+
+// fixturePBF encodes the fixture with dense nodes, a plain node and ways split over two data blocks
+func fixturePBF() []byte {
+	stringTable := [][]byte{{}}
+	stringIndex := map[string]uint64{}
+	str := func(s string) uint64 {
+		if i, ok := stringIndex[s]; ok {
+			return i
+		}
+		stringIndex[s] = uint64(len(stringTable))
+		stringTable = append(stringTable, []byte(s))
+		return stringIndex[s]
+	}
+	// granularity 100 dengan offset, koordinat = 1e-9 * (offset + 100 * nilai)
+	var latOffset, longOffset int64 = -6_000_000_000, 106_000_000_000
+	lat := func(v float64) int64 { return (int64(math.Round(v*1e9)) - latOffset) / 100 }
+	long := func(v float64) int64 { return (int64(math.Round(v*1e9)) - longOffset) / 100 }
+
+	var ids, lats, longs []int64
+	var plain []byte
+	for _, n := range fixtureNodes {
+		if n.denseGroup {
+			ids, lats, longs = append(ids, n.id), append(lats, lat(n.lat)), append(longs, long(n.long))
+			continue
+		}
+		var node []byte
+		node = pbUint(node, 1, zigzag(n.id))
+		if n.pointOnly {
+			node = pbBytes(node, 2, binary.AppendUvarint(nil, str("amenity")))
+			node = pbBytes(node, 3, binary.AppendUvarint(nil, str("restaurant")))
+		}
+		node = pbUint(node, 8, zigzag(lat(n.lat)))
+		node = pbUint(node, 9, zigzag(long(n.long)))
+		plain = pbBytes(plain, 1, node)
+	}
+	var dense []byte
+	dense = pbDeltas(dense, 1, ids)
+	dense = pbDeltas(dense, 8, lats)
+	dense = pbDeltas(dense, 9, longs)
+
+	var ways [][]byte
+	for i, w := range fixtureWays {
+		var keys, vals []byte
+		for k, v := range w.tags {
+			keys = binary.AppendUvarint(keys, str(k))
+			vals = binary.AppendUvarint(vals, str(v))
+		}
+		var way []byte
+		way = pbUint(way, 1, uint64(100+i))
+		way = pbBytes(way, 2, keys)
+		way = pbBytes(way, 3, vals)
+		way = pbDeltas(way, 8, w.refs)
+		ways = append(ways, way)
+	}
+
+	block := func(groups ...[]byte) []byte {
+		var st, b []byte
+		for _, s := range stringTable {
+			st = pbBytes(st, 1, s)
+		}
+		b = pbBytes(b, 1, st)
+		for _, g := range groups {
+			b = pbBytes(b, 2, g)
+		}
+		b = pbUint(b, 17, 100)
+		b = pbUint(b, 19, uint64(latOffset))
+		b = pbUint(b, 20, uint64(longOffset))
+		return b
+	}
+	var waysGroup []byte
+	for _, w := range ways[:3] {
+		waysGroup = pbBytes(waysGroup, 3, w)
+	}
+	var moreWays []byte
+	for _, w := range ways[3:] {
+		moreWays = pbBytes(moreWays, 3, w)
+	}
+
+	var file bytes.Buffer
+	pbBlob(&file, "OSMHeader", pbBytes(nil, 4, []byte("OsmSchema-V0.6")), false)
+	pbBlob(&file, "OSMData", block(pbBytes(nil, 2, dense), plain, waysGroup), true)
+	pbBlob(&file, "OSMData", block(moreWays), false)
+	return file.Bytes()
+}
+
+func loadFixture(t *testing.T, name string, data []byte) *RoadNetwork {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	g, err := LoadRoadNetwork(path)
+	if err != nil {
+		t.Fatalf("LoadRoadNetwork(%s): %v", name, err)
+	}
+	return g
+}
+
+func assertKm(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-6 {
+		t.Errorf("%s = %.6f km, want %.6f km", name, got, want)
+	}
+}
+
+func TestRoadNetworkLoadsOnlyRoadNodes(t *testing.T) {
+	g := loadFixture(t, "fixture.osm", fixtureXML())
+	// footway, POI dan node yang hilang dari extract tidak ikut dimuat
+	if got := g.Nodes(); got != 7 {
+		t.Errorf("Nodes() = %d, want 7", got)
+	}
+}
+
+func TestRoadNetworkOneWay(t *testing.T) {
+	g := loadFixture(t, "fixture.osm", fixtureXML())
+
+	dist, err := g.Distances([]Point{nodeA.point(), nodeC.point()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertKm(t, "A to C", dist[0][1], between(nodeA, nodeB)+between(nodeB, nodeC))
+	// arah barat harus memutar lewat D dan E, footway tidak boleh dipakai
+	assertKm(t, "C to A", dist[1][0], between(nodeC, nodeD)+between(nodeD, nodeE)+between(nodeE, nodeA))
+}
+
+func TestRoadNetworkSnapsToNearestNode(t *testing.T) {
+	g := loadFixture(t, "fixture.osm", fixtureXML())
+	nearB := Point{Lat: -6.2005, Long: 106.8100}
+
+	dist, err := g.Distances([]Point{nearB, nodeC.point()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapKm := Haversine(nearB.Lat, nearB.Long, nodeB.lat, nodeB.long)
+	assertKm(t, "near B to C", dist[0][1], snapKm+between(nodeB, nodeC))
+}
+
+func TestRoadNetworkFallsBackToStraightLine(t *testing.T) {
+	g := loadFixture(t, "fixture.osm", fixtureXML())
+	offRoad := Point{Lat: -6.2500, Long: 106.8500}
+
+	// H tidak terhubung ke A, offRoad lebih dari maxSnapKm dari jalan mana pun
+	points := []Point{nodeA.point(), nodeH.point(), offRoad}
+	dist, err := g.Distances(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range points {
+		for j, q := range points {
+			if i != j {
+				assertKm(t, fmt.Sprintf("dist[%d][%d]", i, j), dist[i][j], Haversine(p.Lat, p.Long, q.Lat, q.Long))
+			}
+		}
+	}
+}
+
+func TestRoadNetworkPBFMatchesXML(t *testing.T) {
+	fromXML := loadFixture(t, "fixture.osm", fixtureXML())
+	fromPBF := loadFixture(t, "fixture.osm.pbf", fixturePBF())
+
+	if fromPBF.Nodes() != fromXML.Nodes() {
+		t.Fatalf("PBF has %d nodes, XML has %d", fromPBF.Nodes(), fromXML.Nodes())
+	}
+	points := []Point{nodeA.point(), nodeB.point(), nodeC.point(), nodeE.point(), nodeH.point(), {Lat: -6.2005, Long: 106.8100}}
+	want, err := fromXML.Distances(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := fromPBF.Distances(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range want {
+		for j := range want[i] {
+			assertKm(t, fmt.Sprintf("dist[%d][%d]", i, j), got[i][j], want[i][j])
+		}
+	}
+}
+
+func TestRoadNetworkRejectsCorruptPBF(t *testing.T) {
+	data := fixturePBF()
+	path := filepath.Join(t.TempDir(), "corrupt.osm.pbf")
+	if err := os.WriteFile(path, data[:len(data)-5], 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRoadNetwork(path); err == nil {
+		t.Fatal("LoadRoadNetwork accepted a truncated PBF file")
+	}
+}
@@ -0,0 +1,276 @@
+package purchase
+
+import (
+	"belimang/src/pkg/entities"
+	"math"
+)
+
+// maxExactStops is the largest number of merchants routed with the exact solver,
+// Held-Karp needs O(n² 2ⁿ) time so bigger baskets use local search instead
+const maxExactStops = 10
+
+// RouteSolver orders the stops of a delivery route. dist holds the distance between every
+// pair of stops, the route starts at stop 0 and ends at the last stop. Solve returns the
+// visiting order, from the first to the last stop, and the length of the route.
+type RouteSolver interface {
+	Solve(dist [][]float64) ([]int, float64)
+}
+
+// NewRouteSolver returns the default solver: exact for small baskets, local search for larger ones
+func NewRouteSolver() RouteSolver {
+	return &adaptiveSolver{
+		maxExact: maxExactStops,
+		exact:    heldKarpSolver{},
+		fallback: localSearchSolver{},
+	}
+}
+
+type adaptiveSolver struct {
+	maxExact int
+	exact    RouteSolver
+	fallback RouteSolver
+}
+
+func (s *adaptiveSolver) Solve(dist [][]float64) ([]int, float64) {
+	// stop terakhir adalah lokasi user, bukan merchant
+	if len(dist)-1 <= s.maxExact {
+		return s.exact.Solve(dist)
+	}
+	return s.fallback.Solve(dist)
+}
+
+// nearestNeighborSolver always moves on to the closest stop not yet visited
+type nearestNeighborSolver struct{}
+
+func (nearestNeighborSolver) Solve(dist [][]float64) ([]int, float64) {
+	if len(dist) <= 1 {
+		return make([]int, len(dist)), 0
+	}
+
+	end := len(dist) - 1
+	visited := make([]bool, len(dist))
+	visited[0] = true
+	route := make([]int, 1, len(dist))
+	total := 0.0
+
+	current := 0
+	for len(route) < end {
+		nearest := -1
+		for i := 1; i < end; i++ {
+			if !visited[i] && (nearest == -1 || dist[current][i] < dist[current][nearest]) {
+				nearest = i
+			}
+		}
+		visited[nearest] = true
+		route = append(route, nearest)
+		total += dist[current][nearest]
+		current = nearest
+	}
+	return append(route, end), total + dist[current][end]
+}
+
+// heldKarpSolver finds the shortest route with the Held-Karp dynamic program
+type heldKarpSolver struct{}
+
+func (heldKarpSolver) Solve(dist [][]float64) ([]int, float64) {
+	if len(dist) <= 3 {
+		return nearestNeighborSolver{}.Solve(dist)
+	}
+
+	// stop di antara awal dan akhir (1..n) dipetakan ke bit 0..n-1
+	end := len(dist) - 1
+	n := end - 1
+	full := 1<<n - 1
+
+	// cost[set][last] adalah rute terpendek dari stop 0 yang mengunjungi set dan berakhir di last
+	cost := make([][]float64, full+1)
+	parent := make([][]int8, full+1)
+	for set := range cost {
+		cost[set] = make([]float64, n)
+		parent[set] = make([]int8, n)
+		for last := range cost[set] {
+			cost[set][last] = math.Inf(1)
+			parent[set][last] = -1
+		}
+	}
+	for last := 0; last < n; last++ {
+		cost[1<<last][last] = dist[0][last+1]
+	}
+
+	for set := 1; set <= full; set++ {
+		for last := 0; last < n; last++ {
+			if set&(1<<last) == 0 || math.IsInf(cost[set][last], 1) {
+				continue
+			}
+			for next := 0; next < n; next++ {
+				if set&(1<<next) != 0 {
+					continue
+				}
+				nextSet := set | 1<<next
+				if c := cost[set][last] + dist[last+1][next+1]; c < cost[nextSet][next] {
+					cost[nextSet][next] = c
+					parent[nextSet][next] = int8(last)
+				}
+			}
+		}
+	}
+
+	best, bestCost := -1, math.Inf(1)
+	for last := 0; last < n; last++ {
+		if c := cost[full][last] + dist[last+1][end]; c < bestCost {
+			best, bestCost = last, c
+		}
+	}
+
+	route := make([]int, end+1)
+	route[end] = end
+	set, last := full, best
+	for i := n; i > 0; i-- {
+		route[i] = last + 1
+		prev := int(parent[set][last])
+		set &^= 1 << last
+		last = prev
+	}
+	return route, bestCost
+}
+
+// localSearchSolver starts from the nearest neighbour route and improves it with
+// 2-opt and Or-opt moves until neither finds a shorter route
+type localSearchSolver struct{}
+
+// epsilon keeps rounding noise from being taken as an improvement
+const epsilon = 1e-9
+
+func (localSearchSolver) Solve(dist [][]float64) ([]int, float64) {
+	route, _ := nearestNeighborSolver{}.Solve(dist)
+	for improved := true; improved; {
+		improved = twoOpt(dist, route) || orOpt(dist, route)
+	}
+	return route, routeLength(dist, route)
+}
+
+// twoOpt reverses the first segment route[i..j] whose reversal shortens the route,
+// the first and last stop stay in place
+func twoOpt(dist [][]float64, route []int) bool {
+	n := len(route)
+	for i := 1; i < n-2; i++ {
+		for j := i + 1; j < n-1; j++ {
+			before := dist[route[i-1]][route[i]] + dist[route[j]][route[j+1]]
+			after := dist[route[i-1]][route[j]] + dist[route[i]][route[j+1]]
+			if after < before-epsilon {
+				for a, b := i, j; a < b; a, b = a+1, b-1 {
+					route[a], route[b] = route[b], route[a]
+				}
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// orOpt moves the first segment of up to three stops that is shorter elsewhere in the route
+func orOpt(dist [][]float64, route []int) bool {
+	n := len(route)
+	for size := 1; size <= 3; size++ {
+		for i := 1; i+size < n; i++ {
+			j := i + size - 1
+			removed := dist[route[i-1]][route[i]] + dist[route[j]][route[j+1]]
+			closed := dist[route[i-1]][route[j+1]]
+
+			for k := 0; k < n-1; k++ {
+				// segmen disisipkan di antara route[k] dan route[k+1]
+				if k >= i-1 && k <= j {
+					continue
+				}
+				added := dist[route[k]][route[i]] + dist[route[j]][route[k+1]] - dist[route[k]][route[k+1]]
+				if closed+added < removed-epsilon {
+					moveSegment(route, i, j, k)
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// moveSegment moves route[i..j] to just after route[k]
+func moveSegment(route []int, i, j, k int) {
+	segment := append([]int{}, route[i:j+1]...)
+	rest := append(append([]int{}, route[:i]...), route[j+1:]...)
+	if k > j {
+		k -= len(segment)
+	}
+	out := append(append(append([]int{}, rest[:k+1]...), segment...), rest[k+1:]...)
+	copy(route, out)
+}
+
+func routeLength(dist [][]float64, route []int) float64 {
+	total := 0.0
+	for i := 1; i < len(route); i++ {
+		total += dist[route[i-1]][route[i]]
+	}
+	return total
+}
+
+// Point is a location on the map
+type Point struct {
+	Lat  float64
+	Long float64
+}
+
+// DistanceProvider measures the distance in km between every pair of points, dist[i][j] is
+// the distance travelled from points[i] to points[j]
+type DistanceProvider interface {
+	Distances(points []Point) ([][]float64, error)
+}
+
+// StraightLine measures distances as the crow flies, used when no road network is configured
+type StraightLine struct{}
+
+func (StraightLine) Distances(points []Point) ([][]float64, error) {
+	dist := make([][]float64, len(points))
+	for i := range dist {
+		dist[i] = make([]float64, len(points))
+		for j := range dist[i] {
+			if i != j {
+				dist[i][j] = Haversine(points[i].Lat, points[i].Long, points[j].Lat, points[j].Long)
+			}
+		}
+	}
+	return dist, nil
+}
+
+// Route is a planned delivery
+type Route struct {
+	Merchants []entities.Merchant // urutan kunjungan, dimulai dari merchant starting point
+	Legs      []float64           // jarak tiap leg dalam km, leg terakhir berakhir di lokasi user
+	Distance  float64
+}
+
+// PlanRoute orders a delivery with the solver: it starts at the starting merchant, visits the
+// other merchants and ends at the user location, so the drop-off leg to the user is included.
+func PlanRoute(solver RouteSolver, distances DistanceProvider, start entities.Merchant, merchants []entities.Merchant, userLat, userLong float64) (Route, error) {
+	stops := append([]entities.Merchant{start}, merchants...)
+	points := make([]Point, 0, len(stops)+1)
+	for _, m := range stops {
+		points = append(points, Point{Lat: m.Lat, Long: m.Long})
+	}
+	points = append(points, Point{Lat: userLat, Long: userLong})
+
+	dist, err := distances.Distances(points)
+	if err != nil {
+		return Route{}, err
+	}
+
+	order, total := solver.Solve(dist)
+	route := Route{
+		Merchants: make([]entities.Merchant, 0, len(stops)),
+		Legs:      make([]float64, 0, len(stops)),
+		Distance:  total,
+	}
+	for i, stop := range order[:len(order)-1] {
+		route.Merchants = append(route.Merchants, stops[stop])
+		route.Legs = append(route.Legs, dist[stop][order[i+1]])
+	}
+	return route, nil
+}
@@ -0,0 +1,146 @@
+package purchase
+
+import (
+	"belimang/src/pkg/entities"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// randomMerchants places n merchants around central Jakarta
+func randomMerchants(rng *rand.Rand, n int) []entities.Merchant {
+	merchants := make([]entities.Merchant, n)
+	for i := range merchants {
+		merchants[i] = entities.Merchant{
+			ID:   uuid.New(),
+			Lat:  -6.2 + rng.Float64()*0.05,
+			Long: 106.8 + rng.Float64()*0.05,
+		}
+	}
+	return merchants
+}
+
+// bruteForce tries every visiting order between the first and the last stop
+func bruteForce(dist [][]float64) float64 {
+	end := len(dist) - 1
+	stops := make([]int, 0, len(dist))
+	for i := 1; i < end; i++ {
+		stops = append(stops, i)
+	}
+	best := math.Inf(1)
+	var permute func(k int)
+	permute = func(k int) {
+		if k == len(stops) {
+			best = math.Min(best, routeLength(dist, append(append([]int{0}, stops...), end)))
+			return
+		}
+		for i := k; i < len(stops); i++ {
+			stops[k], stops[i] = stops[i], stops[k]
+			permute(k + 1)
+			stops[k], stops[i] = stops[i], stops[k]
+		}
+	}
+	permute(0)
+	return best
+}
+
+func checkRoute(t *testing.T, dist [][]float64, route []int, total float64) {
+	t.Helper()
+	if len(route) != len(dist) || route[0] != 0 || route[len(route)-1] != len(dist)-1 {
+		t.Fatalf("route %v does not run from 0 to %d", route, len(dist)-1)
+	}
+	seen := make(map[int]bool)
+	for _, stop := range route {
+		if seen[stop] {
+			t.Fatalf("route %v visits %d twice", route, stop)
+		}
+		seen[stop] = true
+	}
+	if math.Abs(routeLength(dist, route)-total) > 1e-9 {
+		t.Fatalf("reported length %f, route is %f", total, routeLength(dist, route))
+	}
+}
+
+func TestSolversNeverWorseThanNearestNeighbor(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	solvers := map[string]RouteSolver{
+		"heldKarp":    heldKarpSolver{},
+		"localSearch": localSearchSolver{},
+		"default":     NewRouteSolver(),
+	}
+
+	for n := 1; n <= 10; n++ {
+		for round := 0; round < 20; round++ {
+			merchants := randomMerchants(rng, n)
+			greedy, _ := PlanRoute(nearestNeighborSolver{}, StraightLine{}, merchants[0], merchants[1:], -6.21, 106.82)
+
+			for name, solver := range solvers {
+				route, err := PlanRoute(solver, StraightLine{}, merchants[0], merchants[1:], -6.21, 106.82)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(route.Merchants) != n || len(route.Legs) != n || route.Merchants[0].ID != merchants[0].ID {
+					t.Fatalf("%s: route of %d merchants does not start at the starting merchant", name, n)
+				}
+				if route.Distance > greedy.Distance+1e-9 {
+					t.Errorf("%s: %d merchants, route %f km is longer than nearest neighbour %f km", name, n, route.Distance, greedy.Distance)
+				}
+			}
+		}
+	}
+}
+
+func TestHeldKarpIsOptimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for n := 2; n <= 9; n++ {
+		for round := 0; round < 10; round++ {
+			dist := randomMatrix(rng, n)
+			route, total := heldKarpSolver{}.Solve(dist)
+			checkRoute(t, dist, route, total)
+			if want := bruteForce(dist); math.Abs(total-want) > 1e-9 {
+				t.Errorf("%d stops: held-karp %f, optimum %f", n, total, want)
+			}
+		}
+	}
+}
+
+func TestLocalSearchLargeBaskets(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for _, n := range []int{11, 20, 40} {
+		dist := randomMatrix(rng, n+1)
+		route, total := localSearchSolver{}.Solve(dist)
+		checkRoute(t, dist, route, total)
+		if _, greedy := (nearestNeighborSolver{}).Solve(dist); total > greedy+1e-9 {
+			t.Errorf("%d stops: local search %f is longer than nearest neighbour %f", n, total, greedy)
+		}
+	}
+}
+
+// randomMatrix builds the haversine distances between random points
+func randomMatrix(rng *rand.Rand, n int) [][]float64 {
+	merchants := randomMerchants(rng, n)
+	dist := make([][]float64, n)
+	for i := range dist {
+		dist[i] = make([]float64, n)
+		for j := range dist[i] {
+			dist[i][j] = Haversine(merchants[i].Lat, merchants[i].Long, merchants[j].Lat, merchants[j].Long)
+		}
+	}
+	return dist
+}
+
+func benchmarkSolver(b *testing.B, solver RouteSolver, stops int) {
+	dist := randomMatrix(rand.New(rand.NewSource(4)), stops+1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		solver.Solve(dist)
+	}
+}
+
+func BenchmarkNearestNeighbor10(b *testing.B) { benchmarkSolver(b, nearestNeighborSolver{}, 10) }
+func BenchmarkHeldKarp5(b *testing.B)         { benchmarkSolver(b, heldKarpSolver{}, 5) }
+func BenchmarkHeldKarp10(b *testing.B)        { benchmarkSolver(b, heldKarpSolver{}, 10) }
+func BenchmarkLocalSearch10(b *testing.B)     { benchmarkSolver(b, localSearchSolver{}, 10) }
+func BenchmarkLocalSearch30(b *testing.B)     { benchmarkSolver(b, localSearchSolver{}, 30) }
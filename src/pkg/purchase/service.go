@@ -4,29 +4,79 @@ import (
 	"belimang/src/pkg/dtos"
 	"belimang/src/pkg/entities"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// OutOfStockError is returned when ordered items are unavailable or exceed their remaining stock
+type OutOfStockError struct {
+	ItemIDs []uuid.UUID
+}
+
+func (e *OutOfStockError) Error() string {
+	ids := make([]string, len(e.ItemIDs))
+	for i, id := range e.ItemIDs {
+		ids[i] = id.String()
+	}
+	return "out of stock: " + strings.Join(ids, ", ")
+}
+
+var (
+	ErrNoStartingPoint      = errors.New("there must be exactly one order with isStartingPoint = true")
+	ErrInvalidDepartureTime = errors.New("departureTime must be an RFC3339 timestamp")
+	ErrEstimateNotFound     = errors.New("estimate not found")
+	ErrEstimateNotOwned     = errors.New("estimate belongs to another user")
+	ErrInvalidEstimateID    = errors.New("calculatedEstimateId is not valid")
+	ErrEstimateExpired      = errors.New("estimate has expired, please request a new estimate")
+	ErrEstimateUsed         = errors.New("estimate has already been ordered")
+)
+
+// DefaultEstimateTTL is how long an estimate can be ordered when no TTL is configured
+const DefaultEstimateTTL = 15 * time.Minute
+
+// Config holds the tunable settings of the purchase service
+type Config struct {
+	EstimateTTL time.Duration // masa berlaku estimasi sebelum di-order
+	// selisih maksimal (persen dari total estimasi) antara total estimasi dan harga saat ini
+	// yang masih diterima, total estimasi tetap dipakai
+	PriceTolerancePercent float64
+}
+
 // Service is an interface from which our api module can access our repository of all our models
 type Service interface {
 	NearbyMerchant(lat, long float64, params map[string]interface{}) (*dtos.NearbyMerchantResponse, error)
 	Estimate(req entities.EstimateRequest, userID uuid.UUID) (*entities.DeliveryEstimate, error)
+	GetEstimate(estimateID, userID uuid.UUID) (*entities.DeliveryEstimate, error)
 	Order(req entities.OrderRequest, userID uuid.UUID) (string, error)
 	GetOrderData(req map[string]interface{}) ([]map[string]interface{}, error)
 }
 
 type service struct {
-	repository Repository
+	repository  Repository
+	routeSolver RouteSolver
+	distances   DistanceProvider
+	travelTime  TravelTimeModel
+	config      Config
 }
 
 // NewService is used to create a single instance of the service
-func NewService(r Repository) Service {
+func NewService(r Repository, distances DistanceProvider, travelTime TravelTimeModel, config Config) Service {
+	if config.EstimateTTL <= 0 {
+		config.EstimateTTL = DefaultEstimateTTL
+	}
 	return &service{
-		repository: r,
+		repository:  r,
+		routeSolver: NewRouteSolver(),
+		distances:   distances,
+		travelTime:  travelTime,
+		config:      config,
 	}
 }
 
@@ -37,42 +87,65 @@ func (s *service) NearbyMerchant(lat, long float64, params map[string]interface{
 		return nil, err
 	}
 
-	limit := params["limit"].(int)
-	offset := params["offset"].(int)
-	if v, ok := params["limit"]; ok {
-		if l, ok := v.(int); ok && l > 0 {
-			limit = l
-		}
+	// filter dilakukan sebelum paginasi supaya halaman tetap konsisten
+	now := time.Now()
+	openMerchants, err := s.openMerchants(merchants, now)
+	if err != nil {
+		return nil, err
 	}
-	if v, ok := params["offset"]; ok {
-		if o, ok := v.(int); ok && o >= 0 {
-			offset = o
+	openNow, _ := params["openNow"].(bool)
+	tags, _ := params["tags"].([]string)
+	filtered := make([]entities.Merchant, 0, len(merchants))
+	for _, m := range merchants {
+		// merchant yang tidak bisa mengantar sampai lokasi user disembunyikan
+		if !canDeliver(m, lat, long) {
+			continue
+		}
+		if openNow && !openMerchants[m.ID] {
+			continue
 		}
+		filtered = append(filtered, m)
 	}
+	merchants = filtered
 
-	limit_merchants := limit
-	if len(merchants) < limit {
-		limit_merchants = len(merchants)
+	merchantIDs := make([]uuid.UUID, len(merchants))
+	for i, m := range merchants {
+		merchantIDs[i] = m.ID
+	}
+	ratings, err := s.repository.FindRatings(merchantIDs)
+	if err != nil {
+		return nil, err
+	}
+	sortByRating := params["sortBy"] == "rating"
+	if sortByRating {
+		// rating tertinggi dulu, jumlah review sebagai penentu berikutnya, sisanya tetap urut jarak
+		sort.SliceStable(merchants, func(i, j int) bool {
+			a, b := ratings[merchants[i].ID], ratings[merchants[j].ID]
+			if a.Average != b.Average {
+				return a.Average > b.Average
+			}
+			return a.Count > b.Count
+		})
 	}
-	tspMerchants, _ := NearestNeighborTSP(lat, long, merchants[offset:limit_merchants])
 
-	// Apply offset
-	if offset >= len(tspMerchants) {
-		return &dtos.NearbyMerchantResponse{
-			Data: []dtos.MerchantWithItems{},
-			Meta: dtos.MetaResponse{
-				Limit:  limit,
-				Offset: offset,
-				Total:  10,
-			},
-		}, nil
+	limit := 5
+	offset := 0
+	if l, ok := params["limit"].(int); ok && l > 0 {
+		limit = l
+	}
+	if o, ok := params["offset"].(int); ok && o > 0 {
+		offset = o
 	}
 
-	if offset > 0 {
-		tspMerchants = tspMerchants[offset:]
+	// halaman dipotong sekali, offset di luar jumlah merchant menghasilkan halaman kosong
+	n := len(merchants)
+	tspMerchants := merchants[min(offset, n):min(offset+limit, n)]
+	if !sortByRating {
+		tspMerchants, _ = NearestNeighborTSP(lat, long, tspMerchants)
 	}
+
 	// Get merchant IDs
-	merchantIDs := make([]uuid.UUID, len(tspMerchants))
+	merchantIDs = make([]uuid.UUID, len(tspMerchants))
 	for i, m := range tspMerchants {
 		merchantIDs[i] = m.ID
 	}
@@ -81,34 +154,41 @@ func (s *service) NearbyMerchant(lat, long float64, params map[string]interface{
 	if err != nil {
 		return nil, err
 	}
+	var itemIDs []uuid.UUID
+	for _, items := range itemsByMerchant {
+		for _, item := range items {
+			itemIDs = append(itemIDs, item.ID)
+		}
+	}
+	modifiers, err := s.repository.FindModifierGroups(itemIDs)
+	if err != nil {
+		return nil, err
+	}
+	promotions, err := s.repository.FindActivePromotions(merchantIDs, now)
+	if err != nil {
+		return nil, err
+	}
 	// Build response
 	data := make([]dtos.MerchantWithItems, len(tspMerchants))
 	for i, merchant := range tspMerchants {
+		isOpen := openMerchants[merchant.ID]
 		// Convert merchant to response format
-		merchantResp := dtos.MerchantResponse{
-			MerchantID:       uuid.MustParse(merchant.ID.String()),
-			Name:             merchant.Name,
-			MerchantCategory: string(merchant.MerchantCategory),
-			ImageURL:         merchant.ImageUrl,
-			Location: dtos.LocationResponse{
-				Lat:  merchant.Lat,
-				Long: merchant.Long,
-			},
-			CreatedAt: formatNanosToISO8601(merchant.CreatedAt),
-		}
+		merchantResp := dtos.ToMerchantResponse(merchant)
+		merchantResp.IsOpen = &isOpen
+		rating := ratings[merchant.ID]
+		merchantResp.Rating = &rating
 
 		// Convert items to response format
-		items := itemsByMerchant[merchant.ID]
-		itemsResp := make([]dtos.ItemResponse, len(items))
-		for j, item := range items {
-			itemsResp[j] = dtos.ItemResponse{
-				ItemID:          uuid.MustParse(item.ID.String()),
-				Name:            item.Name,
-				ProductCategory: string(item.ProductCategory),
-				Price:           item.Price,
-				ImageURL:        item.ImageUrl,
-				CreatedAt:       formatNanosToISO8601(item.CreatedAt),
+		itemsResp := []dtos.ItemResponse{}
+		for _, item := range itemsByMerchant[merchant.ID] {
+			// dengan filter tag hanya item yang cocok yang ditampilkan
+			if !item.HasTags(tags) {
+				continue
 			}
+			itemResp := dtos.ToItemResponse(item)
+			itemResp.Modifiers = dtos.ToModifierGroupResponses(modifiers[item.ID])
+			itemResp.ApplyPromotions(item, merchant.Timezone, promotions[merchant.ID], now)
+			itemsResp = append(itemsResp, itemResp)
 		}
 
 		data[i] = dtos.MerchantWithItems{
@@ -126,6 +206,29 @@ func (s *service) NearbyMerchant(lat, long float64, params map[string]interface{
 	}, nil
 }
 
+// openMerchants reports for each merchant whether it is open at t
+func (s *service) openMerchants(merchants []entities.Merchant, t time.Time) (map[uuid.UUID]bool, error) {
+	merchantIDs := make([]uuid.UUID, len(merchants))
+	for i, m := range merchants {
+		merchantIDs[i] = m.ID
+	}
+	hours, closures, err := s.repository.FindSchedules(merchantIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	open := make(map[uuid.UUID]bool, len(merchants))
+	for _, m := range merchants {
+		open[m.ID] = entities.IsOpenAt(m.Timezone, hours[m.ID], closures[m.ID], t)
+	}
+	return open, nil
+}
+
+// canDeliver reports whether the user location lies within the delivery radius of the merchant
+func canDeliver(merchant entities.Merchant, userLat, userLong float64) bool {
+	return Haversine(userLat, userLong, merchant.Lat, merchant.Long) <= merchant.MaxDeliveryKm
+}
+
 func Haversine(lat1, lon1, lat2, lon2 float64) float64 {
 	const earthRadius = 6371 // Radius bumi dalam kilometer
 
@@ -150,6 +253,7 @@ func Haversine(lat1, lon1, lat2, lon2 float64) float64 {
 
 }
 
+// NearestNeighborTSP greedily orders merchants outward from the user location, used to list nearby merchants
 func NearestNeighborTSP(userLat, userLong float64, merchants []entities.Merchant) ([]entities.Merchant, float64) {
 	if len(merchants) == 0 {
 		return []entities.Merchant{}, 0
@@ -198,14 +302,52 @@ func in_array(arr []string, target string) bool {
 	return false
 }
 
+// checkItemsAvailable makes sure every requested item was found and belongs to the merchant it was ordered from
+func checkItemsAvailable(itemMerchant map[uuid.UUID]uuid.UUID, items []entities.Items) error {
+	found := make(map[uuid.UUID]uuid.UUID, len(items))
+	for _, item := range items {
+		found[item.ID] = item.MerchantID
+	}
+	for itemID, merchantID := range itemMerchant {
+		if m, ok := found[itemID]; !ok || m != merchantID {
+			return fmt.Errorf("invalid itemId: %s", itemID.String())
+		}
+	}
+	return nil
+}
+
+// checkStock makes sure every item is available and has enough stock for the requested quantity
+func checkStock(quantities map[uuid.UUID]int, items []entities.Items) error {
+	var outOfStock []uuid.UUID
+	for _, item := range items {
+		if !item.IsAvailable || (item.Stock != nil && *item.Stock < quantities[item.ID]) {
+			outOfStock = append(outOfStock, item.ID)
+		}
+	}
+	if len(outOfStock) > 0 {
+		sort.Slice(outOfStock, func(i, j int) bool {
+			return outOfStock[i].String() < outOfStock[j].String()
+		})
+		return &OutOfStockError{ItemIDs: outOfStock}
+	}
+	return nil
+}
+
 func (s *service) Estimate(req entities.EstimateRequest, userID uuid.UUID) (*entities.DeliveryEstimate, error) {
 	merchantIds := make([]uuid.UUID, 0, len(req.Orders)) // kapasitas sesuai jumlah order
+	var startingId uuid.UUID
 	for _, order := range req.Orders {
 		id, err := uuid.Parse(order.MerchantID)
 		if err != nil {
 			return nil, fmt.Errorf("invalid merchantId: %s", order.MerchantID)
 		}
 		merchantIds = append(merchantIds, id)
+		if order.IsStartingPoint {
+			startingId = id
+		}
+	}
+	if startingId == uuid.Nil {
+		return nil, ErrNoStartingPoint
 	}
 	// cek merchantId ada di DB
 	merchants, err := s.repository.FindMerchantById(merchantIds)
@@ -224,88 +366,127 @@ func (s *service) Estimate(req entities.EstimateRequest, userID uuid.UUID) (*ent
 		}
 	}
 
-	//hitung jarak antara user dan merchant starting point
-	//jika > 3 km, maka tolak
-	for _, mec := range req.Orders {
-		if mec.IsStartingPoint {
-			for _, toko := range merchants {
-				if toko.ID.String() == mec.MerchantID {
-					jrk := Haversine(req.UserLocation.Lat, req.UserLocation.Long, toko.Lat, toko.Long)
+	// waktu berangkat menentukan jam sibuk pada estimasi waktu tempuh
+	now := time.Now()
+	departure := now
+	if req.DepartureTime != "" {
+		if departure, err = time.Parse(time.RFC3339, req.DepartureTime); err != nil {
+			return nil, ErrInvalidDepartureTime
+		}
+	}
 
-					if jrk > 3 {
-						return nil, fmt.Errorf("starting point too far")
-					}
-				}
-			}
+	// tolak pesanan ke merchant yang sedang tutup
+	openMerchants, err := s.openMerchants(merchants, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, merchant := range merchants {
+		if !openMerchants[merchant.ID] {
+			return nil, fmt.Errorf("merchant is closed: %s", merchant.ID.String())
+		}
+	}
+
+	//hitung jarak antara user dan setiap merchant
+	//tolak jika melebihi radius antar merchant tersebut
+	for _, toko := range merchants {
+		if !canDeliver(toko, req.UserLocation.Lat, req.UserLocation.Long) {
+			return nil, fmt.Errorf("merchant %s does not deliver this far", toko.Name)
 		}
 	}
 
 	//htung total harga
-	//ambil id items dari order
-	ord_items := make(map[string]int)
-	var itemsId []uuid.UUID
-	for _, order := range req.Orders {
+	//ambil id items dan modifier dari order
+	var lines []orderLine
+	for i, order := range req.Orders {
 		for _, item := range order.Items {
-			ord_items[item.ItemID] = item.Quantity
-			itemsId = append(itemsId, uuid.MustParse(item.ItemID))
+			id, err := uuid.Parse(item.ItemID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid itemId: %s", item.ItemID)
+			}
+			optionIDs := make([]uuid.UUID, len(item.OptionIDs))
+			for j, optionID := range item.OptionIDs {
+				if optionIDs[j], err = uuid.Parse(optionID); err != nil {
+					return nil, fmt.Errorf("invalid optionId: %s", optionID)
+				}
+			}
+			lines = append(lines, orderLine{
+				MerchantID: merchantIds[i],
+				ItemID:     id,
+				Quantity:   item.Quantity,
+				OptionIDs:  optionIDs,
+			})
 		}
 	}
 
-	//ambil items
-	items, err := s.repository.FindItemsById(itemsId)
+	priced, err := s.priceOrder(lines, now)
 	if err != nil {
 		return nil, err
 	}
-	totalHarga := 0.0
-	for _, item := range items {
-		qty := ord_items[item.ID.String()]
-		totalHarga += item.Price * float64(qty)
-	}
-
-	//jika merchant berjumlah 1, tidak perlu TSP
-	//hitung jarak antara user dan merchant
-	//dapatkn waktu tempuh
-	if len(merchantIds) == 1 {
-		for _, merchant := range merchants {
-			if merchant.ID.String() == merchantIds[0].String() {
-				jrk := Haversine(req.UserLocation.Lat, req.UserLocation.Long, merchant.Lat, merchant.Long)
-				waktu_menit := (jrk / 40.0) * 60.0
-
-				ordersJSON, _ := json.Marshal(req.Orders)
-
-				simpan_data := entities.DeliveryEstimate{
-					UserID:            uuid.MustParse("550e8400-e29b-41d4-a716-446655440000"),
-					Orders:            ordersJSON,
-					EstimatedDelivery: waktu_menit,
-					TotalPrice:        totalHarga,
-				}
+	totalHarga := priced.Total
 
-				hasilEstimasi, err := s.repository.simpanEstimate(simpan_data)
-				if err != nil {
-					return nil, err
-				}
-				return hasilEstimasi, nil
-			}
+	// minimal belanja dihitung per merchant
+	for _, toko := range merchants {
+		if priced.Subtotals[toko.ID] < toko.MinOrderValue {
+			return nil, fmt.Errorf("minimum order for merchant %s is %s", toko.Name, toko.MinOrderValue.String())
 		}
 	}
 
-	user_lat := req.UserLocation.Lat
-	user_long := req.UserLocation.Long
+	var promoCodeDiscount entities.Money
+	if req.PromoCode != "" {
+		promoCode, discount, err := s.applyPromoCode(req.PromoCode, userID, priced, now)
+		if err != nil {
+			return nil, err
+		}
+		req.PromoCode = promoCode.Code
+		promoCodeDiscount = discount
+		totalHarga -= discount
+	}
 
-	nearby_merchants, errNearby := s.repository.FindMerchantById(merchantIds)
-	if errNearby != nil {
-		return nil, errNearby
+	// rute dimulai dari merchant starting point, lalu merchant lain, dan berakhir di lokasi user.
+	// dengan satu merchant rute hanya berisi leg dari merchant ke user
+	var start entities.Merchant
+	others := make([]entities.Merchant, 0, len(merchants))
+	for _, merchant := range merchants {
+		if merchant.ID == startingId {
+			start = merchant
+			continue
+		}
+		others = append(others, merchant)
 	}
 
-	_, distance := NearestNeighborTSP(user_lat, user_long, nearby_merchants)
+	route, err := PlanRoute(s.routeSolver, s.distances, start, others, req.UserLocation.Lat, req.UserLocation.Long)
+	if err != nil {
+		return nil, err
+	}
+
+	legMinutes, waktu_menit, err := timeLegs(s.travelTime, route.Legs, req.VehicleType, departure)
+	if err != nil {
+		return nil, err
+	}
+	routeJSON, err := json.Marshal(routeStops(route, legMinutes, req.UserLocation.Lat, req.UserLocation.Long, departure))
+	if err != nil {
+		return nil, err
+	}
 
-	waktu_menit := (distance / 40.0) * 60.0
 	ordersJSON, _ := json.Marshal(req.Orders)
+	pricedItems, err := marshalPricedItems(priced.OrderItems)
+	if err != nil {
+		return nil, err
+	}
 	simpan_data := entities.DeliveryEstimate{
 		UserID:            userID,
 		Orders:            ordersJSON,
 		EstimatedDelivery: waktu_menit,
 		TotalPrice:        totalHarga,
+		CreatedAt:         now.UTC(),
+		PromotionDiscount: priced.Discount,
+		PromoCode:         req.PromoCode,
+		PromoCodeDiscount: promoCodeDiscount,
+		Route:             routeJSON,
+		TotalDistanceKm:   route.Distance,
+		DepartureAt:       departure.UTC(),
+		ExpiresAt:         now.Add(s.config.EstimateTTL).UTC(),
+		PricedItems:       pricedItems,
 	}
 
 	hasilEstimasi, err := s.repository.simpanEstimate(simpan_data)
@@ -316,13 +497,38 @@ func (s *service) Estimate(req entities.EstimateRequest, userID uuid.UUID) (*ent
 
 }
 
+// GetEstimate returns an estimate of the user together with its planned route
+func (s *service) GetEstimate(estimateID, userID uuid.UUID) (*entities.DeliveryEstimate, error) {
+	est, err := s.repository.FindEstimateById(estimateID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrEstimateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if est.UserID != userID {
+		return nil, ErrEstimateNotOwned
+	}
+	return est, nil
+}
+
 func (s *service) Order(req entities.OrderRequest, userID uuid.UUID) (string, error) {
 
-	estID := req.CalculatedEstimateId
-	est, err := s.repository.FindEstimateById(uuid.MustParse(estID))
+	estID, err := uuid.Parse(req.CalculatedEstimateId)
+	if err != nil {
+		return "", ErrInvalidEstimateID
+	}
+	est, err := s.GetEstimate(estID, userID)
 	if err != nil {
 		return "", err
 	}
+	// estimasi hanya bisa di-order sekali dan sebelum kedaluwarsa, dicek ulang saat order disimpan
+	if est.ConsumedAt != nil {
+		return "", ErrEstimateUsed
+	}
+	if !time.Now().Before(est.ExpiresAt) {
+		return "", ErrEstimateExpired
+	}
 
 	var wrappers []entities.OrderWrapper
 	if err := json.Unmarshal(est.Orders, &wrappers); err != nil {
@@ -330,39 +536,64 @@ func (s *service) Order(req entities.OrderRequest, userID uuid.UUID) (string, er
 	}
 
 	//hitung total harga
-	ordItems := make(map[uuid.UUID]int)
-	var itemsId []uuid.UUID
+	var lines []orderLine
 	for _, wp := range wrappers {
 		for _, item := range wp.Items {
-			ordItems[item.ItemID] = item.Quantity
-			itemsId = append(itemsId, item.ItemID)
+			lines = append(lines, orderLine{
+				MerchantID: wp.MerchantID,
+				ItemID:     item.ItemID,
+				Quantity:   item.Quantity,
+				OptionIDs:  item.OptionIDs,
+			})
 		}
 	}
 
-	//ambil items
-	items, err := s.repository.FindItemsById(itemsId)
+	// harga mengikuti promosi yang berlaku saat estimasi dibuat
+	priced, err := s.priceOrder(lines, est.CreatedAt)
 	if err != nil {
 		return "", err
 	}
-	totalHarga := 0.0
-	for _, item := range items {
-		qty := ordItems[item.ID]
-		totalHarga += item.Price * float64(qty)
+
+	// kode promo dipakai ulang sesuai estimasi dan ditukar di dalam transaksi order
+	total := priced.Total
+	var redemption *entities.PromoCodeRedemption
+	if est.PromoCode != "" {
+		promoCode, discount, err := s.applyPromoCode(est.PromoCode, userID, priced, est.CreatedAt)
+		if err != nil {
+			return "", err
+		}
+		total -= discount
+		redemption = &entities.PromoCodeRedemption{
+			PromoCodeID: promoCode.ID,
+			UserID:      userID,
+			Discount:    discount,
+		}
+	}
+
+	// harga dikunci sesuai estimasi selama perubahannya dalam toleransi, selebihnya user mendapat
+	// penawaran baru untuk dikonfirmasi. estimasi tanpa priced_items selalu ditawarkan ulang
+	lockedItems, err := unmarshalPricedItems(est.PricedItems)
+	if err != nil {
+		return "", err
+	}
+	if len(lockedItems) == 0 || !s.withinTolerance(est.TotalPrice, total) {
+		quote, err := s.requote(est, lines, userID, time.Now())
+		if err != nil {
+			return "", err
+		}
+		return "", &PriceChangedError{PreviousTotal: est.TotalPrice, Quote: quote}
+	}
+	// order_items dan potongan kode promo memakai harga estimasi supaya jumlahnya tetap sama dengan total
+	if redemption != nil {
+		redemption.Discount = est.PromoCodeDiscount
 	}
 
-	OrderData, errSimpanOrder := s.repository.SimpanOrders(*est, userID, totalHarga)
+	OrderData, errSimpanOrder := s.repository.SimpanOrders(est.ID, userID, est.TotalPrice, lockedItems, redemption)
 	if errSimpanOrder != nil {
 		return "", errSimpanOrder
 	}
 	return OrderData, nil
 }
-func formatNanosToISO8601(nanos int64) string {
-	sec := nanos / 1e9
-	nsec := nanos % 1e9
-	t := time.Unix(sec, nsec)
-	// Format manual agar tetap ada 9 digit nanodetik
-	return t.Format("2006-01-02T15:04:05.000000000Z07:00")
-}
 func (s *service) GetOrderData(params map[string]interface{}) ([]map[string]interface{}, error) {
 	ordersDB, err := s.repository.FindOrders(params)
 	if err != nil {
@@ -396,8 +627,9 @@ func (s *service) GetOrderData(params map[string]interface{}) ([]map[string]inte
 			"productCategory": row.ProductCategory,
 			"price":           row.Price,
 			"quantity":        row.Quantity,
+			"options":         json.RawMessage(row.Options),
 			"imageUrl":        row.ItemImageURL,
-			"createdAt":       formatNanosToISO8601(row.ItemCreatedAt),
+			"createdAt":       dtos.FormatNanosToISO8601(row.ItemCreatedAt),
 		}
 
 		if existingOrder != nil {
@@ -415,7 +647,7 @@ func (s *service) GetOrderData(params map[string]interface{}) ([]map[string]inte
 					"lat":  row.MerchantLat,
 					"long": row.MerchantLong,
 				},
-				"createdAt": formatNanosToISO8601(row.MerchantCreatedAt),
+				"createdAt": dtos.FormatNanosToISO8601(row.MerchantCreatedAt),
 			}
 
 			newOrder := map[string]interface{}{
@@ -0,0 +1,204 @@
+package purchase
+
+import (
+	"belimang/src/pkg/entities"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+var ErrUnknownVehicle = errors.New("vehicleType is not supported")
+
+// TravelTimeModel turns route distances into travel time
+type TravelTimeModel interface {
+	// LegMinutes returns how long a leg of distanceKm takes with the vehicle when leaving at t,
+	// an empty vehicle means the default vehicle
+	LegMinutes(distanceKm float64, vehicle string, t time.Time) (float64, error)
+}
+
+// TravelTimeConfig describes the vehicle speeds and rush hours of a travel time model,
+// it is read from a YAML or JSON file
+type TravelTimeConfig struct {
+	DefaultVehicle string                    `mapstructure:"defaultVehicle"`
+	Timezone       string                    `mapstructure:"timezone"` // zona waktu jam sibuk
+	Vehicles       map[string]VehicleProfile `mapstructure:"vehicles"`
+	Congestion     []CongestionWindow        `mapstructure:"congestion"`
+}
+
+type VehicleProfile struct {
+	SpeedKmh float64 `mapstructure:"speedKmh"`
+}
+
+// CongestionWindow slows travel down between From and To ("HH:MM") by Multiplier,
+// on the given days only when Days is set (0 = Sunday)
+type CongestionWindow struct {
+	From       string  `mapstructure:"from"`
+	To         string  `mapstructure:"to"`
+	Days       []int   `mapstructure:"days"`
+	Multiplier float64 `mapstructure:"multiplier"`
+}
+
+// DefaultTravelTimeConfig is used when no config file is set, a motorbike at 40 km/h without rush hours
+func DefaultTravelTimeConfig() TravelTimeConfig {
+	return TravelTimeConfig{
+		DefaultVehicle: "motorbike",
+		Timezone:       "Asia/Jakarta",
+		Vehicles: map[string]VehicleProfile{
+			"bicycle":   {SpeedKmh: 15},
+			"motorbike": {SpeedKmh: 40},
+			"car":       {SpeedKmh: 30},
+		},
+	}
+}
+
+// LoadTravelTimeConfig reads a travel time config from a YAML or JSON file
+func LoadTravelTimeConfig(path string) (TravelTimeConfig, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return TravelTimeConfig{}, err
+	}
+
+	var cfg TravelTimeConfig
+	if err := v.Unmarshal(&cfg); err != nil {
+		return TravelTimeConfig{}, err
+	}
+	return cfg, nil
+}
+
+type travelTimeModel struct {
+	defaultVehicle string
+	location       *time.Location
+	speeds         map[string]float64
+	congestion     []congestionWindow
+}
+
+type congestionWindow struct {
+	start, end int // menit sejak tengah malam
+	days       map[int]bool
+	multiplier float64
+}
+
+// NewTravelTimeModel builds a travel time model from its config
+func NewTravelTimeModel(cfg TravelTimeConfig) (TravelTimeModel, error) {
+	m := &travelTimeModel{
+		defaultVehicle: cfg.DefaultVehicle,
+		location:       entities.MerchantLocation(cfg.Timezone),
+		speeds:         make(map[string]float64, len(cfg.Vehicles)),
+	}
+	for name, profile := range cfg.Vehicles {
+		if profile.SpeedKmh <= 0 {
+			return nil, fmt.Errorf("vehicle %s: speedKmh must be greater than 0", name)
+		}
+		m.speeds[name] = profile.SpeedKmh
+	}
+	if _, ok := m.speeds[m.defaultVehicle]; !ok {
+		return nil, fmt.Errorf("default vehicle %q has no profile", m.defaultVehicle)
+	}
+
+	for _, w := range cfg.Congestion {
+		start, err := entities.ParseClock(w.From)
+		if err != nil {
+			return nil, err
+		}
+		end, err := entities.ParseClock(w.To)
+		if err != nil {
+			return nil, err
+		}
+		if w.Multiplier <= 0 {
+			return nil, fmt.Errorf("congestion %s-%s: multiplier must be greater than 0", w.From, w.To)
+		}
+		window := congestionWindow{start: start, end: end, multiplier: w.Multiplier}
+		if len(w.Days) > 0 {
+			window.days = make(map[int]bool, len(w.Days))
+			for _, d := range w.Days {
+				window.days[d] = true
+			}
+		}
+		m.congestion = append(m.congestion, window)
+	}
+	return m, nil
+}
+
+func (m *travelTimeModel) LegMinutes(distanceKm float64, vehicle string, t time.Time) (float64, error) {
+	if vehicle == "" {
+		vehicle = m.defaultVehicle
+	}
+	speed, ok := m.speeds[vehicle]
+	if !ok {
+		return 0, ErrUnknownVehicle
+	}
+	return distanceKm / speed * 60 * m.multiplier(t), nil
+}
+
+// multiplier returns the congestion multiplier at t, the slowest window wins when they overlap
+func (m *travelTimeModel) multiplier(t time.Time) float64 {
+	local := t.In(m.location)
+	minute := local.Hour()*60 + local.Minute()
+	day := int(local.Weekday())
+
+	result := 1.0
+	for _, w := range m.congestion {
+		if w.days != nil && !w.days[day] {
+			continue
+		}
+		var active bool
+		if w.start < w.end {
+			active = minute >= w.start && minute < w.end
+		} else {
+			// jendela melewati tengah malam
+			active = minute >= w.start || minute < w.end
+		}
+		if active && w.multiplier > result {
+			result = w.multiplier
+		}
+	}
+	return result
+}
+
+// timeLegs returns the travel time of every leg of a route and their sum,
+// each leg leaves when the previous one arrives
+func timeLegs(model TravelTimeModel, legs []float64, vehicle string, departure time.Time) ([]float64, float64, error) {
+	minutes := make([]float64, len(legs))
+	total := 0.0
+	for i, km := range legs {
+		m, err := model.LegMinutes(km, vehicle, departure.Add(time.Duration(total*float64(time.Minute))))
+		if err != nil {
+			return nil, 0, err
+		}
+		minutes[i] = m
+		total += m
+	}
+	return minutes, total, nil
+}
+
+// routeStops lays out a timed route as the stops of an estimate: the starting merchant,
+// the other merchants in visiting order and finally the user location
+func routeStops(route Route, legMinutes []float64, userLat, userLong float64, departure time.Time) []entities.RouteStop {
+	stops := make([]entities.RouteStop, 0, len(route.Merchants)+1)
+	for _, m := range route.Merchants {
+		id := m.ID
+		stops = append(stops, entities.RouteStop{
+			MerchantID: &id,
+			Name:       m.Name,
+			Location:   entities.Location{Lat: m.Lat, Long: m.Long},
+		})
+	}
+	stops = append(stops, entities.RouteStop{
+		Location: entities.Location{Lat: userLat, Long: userLong},
+	})
+
+	// leg i menuju stop i+1, stop pertama adalah titik berangkat
+	elapsed := 0.0
+	stops[0].ArrivalAt = departure.UTC()
+	for i, km := range route.Legs {
+		elapsed += legMinutes[i]
+		stops[i+1].DistanceKm = km
+		stops[i+1].Minutes = legMinutes[i]
+		stops[i+1].ArrivalMinutes = elapsed
+		stops[i+1].ArrivalAt = departure.Add(time.Duration(elapsed * float64(time.Minute))).UTC()
+	}
+	return stops
+}
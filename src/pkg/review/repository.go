@@ -0,0 +1,133 @@
+package review
+
+import (
+	"belimang/src/pkg/entities"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// uniqueViolation is the SQLSTATE postgres reports when a unique constraint is violated
+const uniqueViolation = "23505"
+
+// Repository interface allows us to access the CRUD Operations here.
+type Repository interface {
+	HasOrderedFrom(userId, orderId, merchantId uuid.UUID) (bool, error)
+	FindReviewByOrder(orderId, merchantId uuid.UUID) (*entities.Review, error)
+	CreateReview(review *entities.Review) (*entities.Review, error)
+	FindReviews(params map[string]interface{}) ([]entities.Review, int64, error)
+	FindReviewById(reviewId uuid.UUID, params map[string]interface{}) (*entities.Review, error)
+	UpdateReview(review *entities.Review) (*entities.Review, error)
+}
+type repository struct {
+	DB *gorm.DB
+}
+
+// NewRepo is the single instance repo that is being created.
+func NewRepo(db *gorm.DB) Repository {
+	return &repository{
+		DB: db,
+	}
+}
+
+// HasOrderedFrom reports whether the order was placed by the user and contains items of the merchant
+func (r *repository) HasOrderedFrom(userId, orderId, merchantId uuid.UUID) (bool, error) {
+	var count int64
+	err := r.DB.
+		Table("orders o").
+		Joins("JOIN order_items oi ON oi.order_id = o.id").
+		Where("o.id = ? AND o.user_id = ? AND oi.merchant_id = ?", orderId, userId, merchantId).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindReviewByOrder retrieves the review of a merchant for an order, returning nil when it does not exist
+func (r *repository) FindReviewByOrder(orderId, merchantId uuid.UUID) (*entities.Review, error) {
+	var review entities.Review
+	if err := r.DB.Where("order_id = ? AND merchant_id = ?", orderId, merchantId).First(&review).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &review, nil
+}
+
+// CreateReview stores a review, returning ErrAlreadyReviewed when the order already has a review
+// of the merchant, e.g. when two submissions of the same review race each other
+func (r *repository) CreateReview(review *entities.Review) (*entities.Review, error) {
+	if err := r.DB.Create(review).Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == "uq_reviews_order_merchant" {
+			return nil, ErrAlreadyReviewed
+		}
+		return nil, err
+	}
+	return review, nil
+}
+
+// FindReviews returns a page of reviews matching the given filters together with the total match count
+func (r *repository) FindReviews(params map[string]interface{}) ([]entities.Review, int64, error) {
+	var reviews []entities.Review
+	var total int64
+
+	query := r.scoped(params)
+
+	if v, ok := params["merchantId"].(uuid.UUID); ok {
+		query = query.Where("reviews.merchant_id = ?", v)
+	}
+
+	if v, ok := params["isHidden"].(bool); ok {
+		query = query.Where("reviews.is_hidden = ?", v)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("reviews.created_at DESC").
+		Offset(params["offset"].(int)).
+		Limit(params["limit"].(int)).
+		Find(&reviews).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return reviews, total, nil
+}
+
+// FindReviewById retrieves a review by its ID, returning nil when it does not exist
+func (r *repository) FindReviewById(reviewId uuid.UUID, params map[string]interface{}) (*entities.Review, error) {
+	var review entities.Review
+	if err := r.scoped(params).Where("reviews.id = ?", reviewId).First(&review).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *repository) UpdateReview(review *entities.Review) (*entities.Review, error) {
+	if err := r.DB.Save(review).Error; err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// scoped restricts the reviews to merchants owned by params["ownerId"] when it is set
+func (r *repository) scoped(params map[string]interface{}) *gorm.DB {
+	query := r.DB.Model(&entities.Review{})
+	if v, ok := params["ownerId"].(uuid.UUID); ok {
+		query = query.
+			Joins("JOIN merchants m ON m.id = reviews.merchant_id").
+			Where("m.owner_id = ?", v)
+	}
+	return query
+}
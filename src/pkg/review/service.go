@@ -0,0 +1,146 @@
+package review
+
+import (
+	"belimang/src/pkg/dtos"
+	"belimang/src/pkg/entities"
+	"belimang/src/pkg/merchant"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrReviewNotFound   = errors.New("review not found")
+	ErrReviewNotAllowed = errors.New("only merchants of your own orders can be reviewed")
+	ErrAlreadyReviewed  = errors.New("this merchant has already been reviewed for the order")
+)
+
+// Service is an interface from which our api module can access our repository of all our models
+type Service interface {
+	CreateReview(userId, merchantId uuid.UUID, req entities.RequestReview) (*dtos.ReviewResponse, error)
+	FetchMerchantReviews(merchantId uuid.UUID, params map[string]interface{}) (*dtos.ReviewListResponse, error)
+	FetchReviews(actor merchant.Actor, params map[string]interface{}) (*dtos.AdminReviewListResponse, error)
+	ModerateReview(actor merchant.Actor, reviewId uuid.UUID, req entities.ModerateReviewRequest) (*dtos.AdminReviewResponse, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+// NewService is used to create a single instance of the service
+func NewService(r Repository) Service {
+	return &service{
+		repository: r,
+	}
+}
+
+// CreateReview is a service layer that helps a user review a merchant they ordered from
+func (s *service) CreateReview(userId, merchantId uuid.UUID, req entities.RequestReview) (*dtos.ReviewResponse, error) {
+	orderId, err := uuid.Parse(req.OrderID)
+	if err != nil {
+		return nil, ErrReviewNotAllowed
+	}
+
+	// hanya order milik user yang berisi item dari merchant ini
+	ordered, err := s.repository.HasOrderedFrom(userId, orderId, merchantId)
+	if err != nil {
+		return nil, err
+	}
+	if !ordered {
+		return nil, ErrReviewNotAllowed
+	}
+
+	existing, err := s.repository.FindReviewByOrder(orderId, merchantId)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrAlreadyReviewed
+	}
+
+	review, err := s.repository.CreateReview(&entities.Review{
+		OrderID:    orderId,
+		MerchantID: merchantId,
+		UserID:     userId,
+		Rating:     req.Rating,
+		Comment:    req.Comment,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := dtos.ToReviewResponse(*review)
+	return &res, nil
+}
+
+// FetchMerchantReviews is a service layer that helps list the visible reviews of a merchant
+func (s *service) FetchMerchantReviews(merchantId uuid.UUID, params map[string]interface{}) (*dtos.ReviewListResponse, error) {
+	params["merchantId"] = merchantId
+	params["isHidden"] = false
+
+	reviews, total, err := s.repository.FindReviews(params)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]dtos.ReviewResponse, len(reviews))
+	for i, r := range reviews {
+		data[i] = dtos.ToReviewResponse(r)
+	}
+
+	return &dtos.ReviewListResponse{
+		Data: data,
+		Meta: dtos.MetaResponse{
+			Limit:  params["limit"].(int),
+			Offset: params["offset"].(int),
+			Total:  int(total),
+		},
+	}, nil
+}
+
+// FetchReviews is a service layer that helps list reviews for moderation
+func (s *service) FetchReviews(actor merchant.Actor, params map[string]interface{}) (*dtos.AdminReviewListResponse, error) {
+	reviews, total, err := s.repository.FindReviews(actor.Scope(params))
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]dtos.AdminReviewResponse, len(reviews))
+	for i, r := range reviews {
+		data[i] = dtos.ToAdminReviewResponse(r)
+	}
+
+	return &dtos.AdminReviewListResponse{
+		Data: data,
+		Meta: dtos.MetaResponse{
+			Limit:  params["limit"].(int),
+			Offset: params["offset"].(int),
+			Total:  int(total),
+		},
+	}, nil
+}
+
+// ModerateReview is a service layer that helps hide or restore a review
+func (s *service) ModerateReview(actor merchant.Actor, reviewId uuid.UUID, req entities.ModerateReviewRequest) (*dtos.AdminReviewResponse, error) {
+	review, err := s.repository.FindReviewById(reviewId, actor.Scope(map[string]interface{}{}))
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		return nil, ErrReviewNotFound
+	}
+
+	review.IsHidden = *req.IsHidden
+	review.HiddenReason = ""
+	if review.IsHidden {
+		review.HiddenReason = req.Reason
+	}
+
+	review, err = s.repository.UpdateReview(review)
+	if err != nil {
+		return nil, err
+	}
+
+	res := dtos.ToAdminReviewResponse(*review)
+	return &res, nil
+}
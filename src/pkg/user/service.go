@@ -134,8 +134,8 @@ func (s *service) Login(username, password, role string) (string, *entities.User
 		return "", nil, ErrInvalidCredentials
 	}
 
-	// Check if user role matches
-	if user.Role != role {
+	// Check if user role matches, superadmins sign in through the admin login
+	if user.Role != role && !(role == entities.RoleAdmin && user.Role == entities.RoleSuperAdmin) {
 		return "", nil, ErrInvalidCredentials
 	}
 